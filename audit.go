@@ -0,0 +1,169 @@
+package sql
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/ioswarm/golik"
+)
+
+// UserOption is the golik option holding the acting user of a command,
+// it is written to the created_by and updated_by audit fields.
+const UserOption = "sql.user"
+
+type contextKey struct{ name string }
+
+var userKey = &contextKey{"user"}
+
+// WithUser returns a copy of the given context acting as the given user.
+func WithUser(ctx context.Context, user string) context.Context {
+	return context.WithValue(ctx, userKey, user)
+}
+
+// Clock returns the current time, it is replaceable for tests.
+type Clock func() time.Time
+
+// ActorFunc returns the user acting in the given context.
+type ActorFunc func(golik.CloveContext) string
+
+// optionContext is implemented by golik contexts giving access to the options of a command.
+type optionContext interface {
+	Option(key string) (interface{}, bool)
+}
+
+// ContextActor returns the user set with WithUser or the UserOption of the golik context.
+func ContextActor(ctx golik.CloveContext) string {
+	if c, ok := ctx.(context.Context); ok {
+		if v := c.Value(userKey); v != nil {
+			return fmt.Sprint(v)
+		}
+	}
+	if oc, ok := ctx.(optionContext); ok {
+		if v, ok := oc.Option(UserOption); ok && v != nil {
+			return fmt.Sprint(v)
+		}
+	}
+	return ""
+}
+
+// Audit names the fields written on create and update. Empty names fall back to
+// the fields tagged with `sql:",audit=createdAt"` and so on.
+type Audit struct {
+	CreatedAt string
+	CreatedBy string
+	UpdatedAt string
+	UpdatedBy string
+}
+
+type auditFields struct {
+	createdAt Field
+	createdBy Field
+	updatedAt Field
+	updatedBy Field
+}
+
+func auditField(builder EntityBuilder, name string, kind string, ftype reflect.Type) (Field, error) {
+	result, err := lookupField(builder, "audit", name, func(tag FieldTag) bool {
+		return strings.EqualFold(strings.ReplaceAll(tag.Audit, "_", ""), kind)
+	})
+	if err != nil || result == nil {
+		return nil, err
+	}
+
+	t := result.Field().Type
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t != ftype {
+		return nil, fmt.Errorf("Audit field %v must be of type %v", result.Name(), ftype)
+	}
+	return result, nil
+}
+
+func auditFieldsOf(builder EntityBuilder, audit Audit) (*auditFields, error) {
+	var err error
+	result := &auditFields{}
+	if result.createdAt, err = auditField(builder, audit.CreatedAt, "createdAt", timetype); err != nil {
+		return nil, err
+	}
+	if result.createdBy, err = auditField(builder, audit.CreatedBy, "createdBy", stringtype); err != nil {
+		return nil, err
+	}
+	if result.updatedAt, err = auditField(builder, audit.UpdatedAt, "updatedAt", timetype); err != nil {
+		return nil, err
+	}
+	if result.updatedBy, err = auditField(builder, audit.UpdatedBy, "updatedBy", stringtype); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// created returns the names of the fields never written by updates.
+func (a *auditFields) created() []string {
+	result := make([]string, 0, 2)
+	for _, fld := range []Field{a.createdAt, a.createdBy} {
+		if fld != nil {
+			result = append(result, fld.Name())
+		}
+	}
+	return result
+}
+
+func setAuditValue(entity interface{}, fld Field, value interface{}) {
+	v, _ := fieldByIndex(reflect.ValueOf(entity).Elem(), fld.Field().Index, true)
+	val := reflect.ValueOf(value)
+	if v.Kind() == reflect.Ptr {
+		ptr := reflect.New(v.Type().Elem())
+		ptr.Elem().Set(val)
+		val = ptr
+	}
+	v.Set(val)
+}
+
+func (h *sqlHandler) now() time.Time {
+	if h.clock != nil {
+		return h.clock()
+	}
+	return time.Now()
+}
+
+// audit writes the audit fields of the given entity and returns the written fields. The created
+// fields are only written on create. If no user is acting, the names of the user fields are
+// returned as omitted, they must not be written so stored users are kept and not claimed by clients.
+func (h *sqlHandler) audit(ctx golik.CloveContext, entity interface{}, create bool) ([]Field, []string) {
+	now := h.now()
+	user := ""
+	if h.actor != nil {
+		user = h.actor(ctx)
+	}
+
+	result := make([]Field, 0, 4)
+	omitted := make([]string, 0, 2)
+	set := func(fld Field, value interface{}) {
+		if fld != nil {
+			setAuditValue(entity, fld, value)
+			result = append(result, fld)
+		}
+	}
+	setUser := func(fld Field) {
+		if fld == nil {
+			return
+		}
+		if user == "" {
+			omitted = append(omitted, fld.Name())
+			return
+		}
+		set(fld, user)
+	}
+
+	if create {
+		set(h.auditing.createdAt, now)
+		setUser(h.auditing.createdBy)
+	}
+	set(h.auditing.updatedAt, now)
+	setUser(h.auditing.updatedBy)
+	return result, omitted
+}
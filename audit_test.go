@@ -0,0 +1,75 @@
+package sql
+
+import (
+	"database/sql"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/ioswarm/golik"
+)
+
+type testAudited struct {
+	ID        int64
+	CreatedAt time.Time `sql:",audit=createdAt"`
+	CreatedBy string    `sql:",audit=createdBy"`
+	UpdatedAt time.Time `sql:",audit=updatedAt"`
+	UpdatedBy *string   `sql:",audit=updatedBy"`
+}
+
+func TestAudit(t *testing.T) {
+	now := time.Date(2020, 5, 17, 10, 30, 0, 0, time.UTC)
+	forged := "mallory"
+
+	tests := []struct {
+		name    string
+		user    string
+		create  bool
+		written []string
+		omitted []string
+	}{
+		{"create", "alice", true, []string{"CreatedAt", "CreatedBy", "UpdatedAt", "UpdatedBy"}, []string{}},
+		{"update", "alice", false, []string{"UpdatedAt", "UpdatedBy"}, []string{}},
+		{"create without user", "", true, []string{"CreatedAt", "UpdatedAt"}, []string{"CreatedBy", "UpdatedBy"}},
+		{"update without user", "", false, []string{"UpdatedAt"}, []string{"UpdatedBy"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			user := tt.user
+			h, err := NewHandler(&HandlerSettings{
+				Database: &sql.DB{},
+				Type:     reflect.TypeOf(testAudited{}),
+				Clock:    func() time.Time { return now },
+				Actor:    func(golik.CloveContext) string { return user },
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			entity := &testAudited{CreatedBy: forged, UpdatedBy: &forged}
+			written, omitted := h.(*sqlHandler).audit(nil, entity, tt.create)
+
+			names := make([]string, len(written))
+			for i, fld := range written {
+				names[i] = fld.Name()
+			}
+			if !reflect.DeepEqual(names, tt.written) {
+				t.Errorf("written = %v, want %v", names, tt.written)
+			}
+			if !reflect.DeepEqual(omitted, tt.omitted) {
+				t.Errorf("omitted = %v, want %v", omitted, tt.omitted)
+			}
+
+			if !entity.UpdatedAt.Equal(now) {
+				t.Errorf("UpdatedAt = %v, want %v", entity.UpdatedAt, now)
+			}
+			if tt.create && !entity.CreatedAt.Equal(now) {
+				t.Errorf("CreatedAt = %v, want %v", entity.CreatedAt, now)
+			}
+			if tt.user != "" && *entity.UpdatedBy != tt.user {
+				t.Errorf("UpdatedBy = %v, want %v", *entity.UpdatedBy, tt.user)
+			}
+		})
+	}
+}
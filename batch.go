@@ -0,0 +1,426 @@
+package sql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/ioswarm/golik"
+)
+
+// BatchMode defines how failures of single rows affect a batch.
+type BatchMode int
+
+const (
+	// BatchAllOrNothing writes all rows in one transaction, which is rolled back on the first failure.
+	// The chunk size only limits the rows combined into one statement.
+	BatchAllOrNothing BatchMode = iota
+	// BatchBestEffort commits every chunk on its own and writes all rows that do not fail.
+	BatchBestEffort
+)
+
+// DefaultBatchSize is the number of rows per chunk if neither the command nor the pool configures it.
+const DefaultBatchSize = 1000
+
+// BatchError is the failure of a single row of a batch.
+type BatchError struct {
+	// Index is the position of the row in the entities or ids of the command,
+	// it is -1 if the batch failed as a whole, e.g. on commit.
+	Index int
+	Err   error
+}
+
+func (e *BatchError) Error() string {
+	if e.Index < 0 {
+		return fmt.Sprintf("Batch: %v", e.Err)
+	}
+	return fmt.Sprintf("Row %d: %v", e.Index, e.Err)
+}
+
+func (e *BatchError) Unwrap() error {
+	return e.Err
+}
+
+// BatchResult is the reply of batch commands.
+type BatchResult struct {
+	// Succeeded is the number of written rows, it is zero if an all-or-nothing batch failed.
+	Succeeded int
+	Errors    []*BatchError
+}
+
+// Err returns the first failure of the batch or nil.
+func (r *BatchResult) Err() error {
+	if len(r.Errors) == 0 {
+		return nil
+	}
+	return r.Errors[0]
+}
+
+// MultiRowInserter is implemented by dialects supporting several rows in the VALUES clause of an insert.
+type MultiRowInserter interface {
+	// MaxInsertRows returns the maximum number of rows of one insert with the given number of columns.
+	MaxInsertRows(columns int) int
+}
+
+func parameterRows(parameters int, columns int) int {
+	if columns == 0 || parameters < columns {
+		return 1
+	}
+	return parameters / columns
+}
+
+func (d *db2Dialect) MaxInsertRows(columns int) int {
+	return parameterRows(32767, columns)
+}
+
+func (d *postgresDialect) MaxInsertRows(columns int) int {
+	return parameterRows(65535, columns)
+}
+
+func (d *sqliteDialect) MaxInsertRows(columns int) int {
+	return parameterRows(999, columns)
+}
+
+func (d *mysqlDialect) MaxInsertRows(columns int) int {
+	return parameterRows(65535, columns)
+}
+
+func (d *sqlServerDialect) MaxInsertRows(columns int) int {
+	if rows := parameterRows(2099, columns); rows < 1000 {
+		return rows
+	}
+	return 1000
+}
+
+// batchRow is a single statement of a batch. Rows of the same query may be combined
+// into one statement with multi, rows with exec are executed on their own.
+type batchRow struct {
+	index   int
+	query   string
+	args    []interface{}
+	multi   func(rows int) string
+	maxRows int
+	exec    func(c context.Context, ctx golik.CloveContext, tx *sql.Tx) error
+	check   func(c context.Context, ctx golik.CloveContext, tx *sql.Tx, res sql.Result) error
+	done    func()
+}
+
+func (h *sqlHandler) batchSize(size int) int {
+	if size > 0 {
+		return size
+	}
+	if h.chunkSize > 0 {
+		return h.chunkSize
+	}
+	return DefaultBatchSize
+}
+
+func (h *sqlHandler) checkEntity(entity interface{}) error {
+	if reflect.TypeOf(entity) != reflect.PtrTo(h.itype) {
+		return fmt.Errorf("Entity must be of type %v, got %T", reflect.PtrTo(h.itype), entity)
+	}
+	return nil
+}
+
+func (h *sqlHandler) createRow(ctx golik.CloveContext, index int, entity interface{}) (*batchRow, error) {
+	if err := h.checkEntity(entity); err != nil {
+		return nil, err
+	}
+
+	h.initVersion(entity)
+	_, omitted := h.audit(ctx, entity, true)
+	fields := h.insertable(entity, omitted...)
+	vals, err := h.builder.ValuesOf(entity, fields)
+	if err != nil {
+		return nil, err
+	}
+	fields, vals = h.writeActive(fields, vals)
+
+	row := &batchRow{
+		index: index,
+		query: h.buildInsert(fields),
+		args:  vals,
+	}
+	if len(h.generated) > 0 {
+		// generated values are read back per row into a copy, the entity gets them after commit
+		written := reflect.New(h.itype)
+		row.exec = func(c context.Context, ctx golik.CloveContext, tx *sql.Tx) error {
+			written.Elem().Set(reflect.ValueOf(entity).Elem())
+			return h.insert(c, ctx, tx, row.query, row.args, written.Interface())
+		}
+		row.done = func() {
+			reflect.ValueOf(entity).Elem().Set(written.Elem())
+		}
+		return row, nil
+	}
+	if inserter, ok := h.dialect.(MultiRowInserter); ok {
+		values := fmt.Sprintf(", (%v)", strings.Join(h.parameters(fields), ", "))
+		row.multi = func(rows int) string {
+			return row.query + strings.Repeat(values, rows-1)
+		}
+		row.maxRows = inserter.MaxInsertRows(len(fields))
+	}
+	return row, nil
+}
+
+func (h *sqlHandler) updateRow(ctx golik.CloveContext, index int, entity interface{}) (*batchRow, error) {
+	if err := h.checkEntity(entity); err != nil {
+		return nil, err
+	}
+
+	id, err := h.builder.ValueOf(entity, h.indexField)
+	if err != nil {
+		return nil, err
+	}
+
+	_, omitted := h.audit(ctx, entity, false)
+	fields := h.builder.Writable(entity, append(h.writeExpects(), omitted...)...)
+	vals, err := h.builder.ValuesOf(entity, fields)
+	if err != nil {
+		return nil, err
+	}
+
+	checkVersion := h.version != nil
+	version := int64(0)
+	args := append(vals, id)
+	if checkVersion {
+		version = h.versionOf(entity)
+		args = append(args, version)
+	}
+
+	row := &batchRow{
+		index: index,
+		query: h.buildUpdate(fields, checkVersion),
+		args:  args,
+		check: func(c context.Context, ctx golik.CloveContext, tx *sql.Tx, res sql.Result) error {
+			if affected, err := res.RowsAffected(); err == nil && affected == 0 {
+				return h.unchanged(c, ctx, tx, id, version, checkVersion)
+			}
+			return nil
+		},
+	}
+	if checkVersion {
+		row.done = func() {
+			h.setVersion(entity, version+1)
+		}
+	}
+	return row, nil
+}
+
+func (h *sqlHandler) deleteRow(index int, id interface{}) *batchRow {
+	if h.deleted != nil {
+		return &batchRow{
+			index: index,
+			query: fmt.Sprintf("UPDATE %v SET %v = ? WHERE %v = ? AND %v", h.tablePath(), h.quote(h.deleted.SQLName()), h.quote(h.indexCol), h.isActive()),
+			args:  []interface{}{h.deletedValue(), id},
+			check: h.affected(id),
+		}
+	}
+	return &batchRow{
+		index: index,
+		query: h.buildDelete(),
+		args:  []interface{}{id},
+		check: h.affected(id),
+	}
+}
+
+// affected fails with ErrNotFound if no row was written.
+func (h *sqlHandler) affected(id interface{}) func(context.Context, golik.CloveContext, *sql.Tx, sql.Result) error {
+	return func(c context.Context, ctx golik.CloveContext, tx *sql.Tx, res sql.Result) error {
+		if affected, err := res.RowsAffected(); err == nil && affected == 0 {
+			return notFound(id)
+		}
+		return nil
+	}
+}
+
+// execRows executes the given rows with statements prepared once per query. Consecutive rows of
+// the same query are combined up to the given number of rows if possible. On failure the position
+// of the failed row is returned, which is the first row of a combined statement.
+func (h *sqlHandler) execRows(ctx golik.CloveContext, tx *sql.Tx, rows []*batchRow, combine int) (int, error) {
+	stmts := make(map[string]*sql.Stmt)
+	defer func() {
+		for _, stmt := range stmts {
+			stmt.Close()
+		}
+	}()
+
+	for i := 0; i < len(rows); {
+		row := rows[i]
+		n := 1
+		if row.multi != nil {
+			for i+n < len(rows) && n < combine && n < row.maxRows && rows[i+n].query == row.query {
+				n++
+			}
+		}
+
+		qry, args := row.query, row.args
+		if n > 1 {
+			qry = row.multi(n)
+			args = make([]interface{}, 0, n*len(row.args))
+			for _, r := range rows[i : i+n] {
+				args = append(args, r.args...)
+			}
+		}
+
+		if err := h.execRow(ctx, tx, stmts, row, qry, args); err != nil {
+			return i, err
+		}
+		i += n
+	}
+	return -1, nil
+}
+
+func (h *sqlHandler) execRow(ctx golik.CloveContext, tx *sql.Tx, stmts map[string]*sql.Stmt, row *batchRow, qry string, args []interface{}) error {
+	c, cancel := withTimeout(ctx, h.writeTimeout)
+	defer cancel()
+
+	if row.exec != nil {
+		return row.exec(c, ctx, tx)
+	}
+
+	stmt, ok := stmts[qry]
+	if !ok {
+		var err error
+		stmt, err = h.prepare(c, ctx, tx, qry)
+		if err != nil {
+			return err
+		}
+		stmts[qry] = stmt
+	}
+
+	res, err := stmt.ExecContext(c, args...)
+	if err != nil {
+		return err
+	}
+	if row.check != nil {
+		return row.check(c, ctx, tx, res)
+	}
+	return nil
+}
+
+// execBatch executes the given rows in a new transaction. On failure the position of the failed
+// row is returned, -1 if the transaction failed as a whole.
+func (h *sqlHandler) execBatch(ctx golik.CloveContext, rows []*batchRow, combine int) (int, error) {
+	c, cancel := withTimeout(ctx, 0)
+	defer cancel()
+
+	tx, err := h.database.BeginTx(c, nil)
+	if err != nil {
+		return -1, err
+	}
+	defer tx.Rollback()
+
+	if failed, err := h.execRows(ctx, tx, rows, combine); err != nil {
+		return failed, err
+	}
+	if err := tx.Commit(); err != nil {
+		return -1, err
+	}
+
+	for _, row := range rows {
+		if row.done != nil {
+			row.done()
+		}
+	}
+	return -1, nil
+}
+
+func (h *sqlHandler) batchError(index int, err error) *BatchError {
+	return &BatchError{Index: index, Err: h.translate(err)}
+}
+
+// runBatch executes the given rows, all-or-nothing batches in one transaction and best-effort batches
+// in one transaction per chunk. Failed combined statements are repeated row by row to report the failed rows.
+func (h *sqlHandler) runBatch(ctx golik.CloveContext, rows []*batchRow, mode BatchMode, size int) *BatchResult {
+	result := &BatchResult{Errors: make([]*BatchError, 0)}
+	if len(rows) == 0 {
+		return result
+	}
+
+	size = h.batchSize(size)
+	if mode == BatchAllOrNothing {
+		failed, err := h.execBatch(ctx, rows, size)
+		if err != nil && failed >= 0 && rows[failed].multi != nil {
+			failed, err = h.execBatch(ctx, rows, 1)
+		}
+		if err != nil {
+			index := -1
+			if failed >= 0 {
+				index = rows[failed].index
+			}
+			result.Errors = append(result.Errors, h.batchError(index, err))
+			return result
+		}
+		result.Succeeded = len(rows)
+		return result
+	}
+
+	for from := 0; from < len(rows); from += size {
+		to := from + size
+		if to > len(rows) {
+			to = len(rows)
+		}
+		chunk := rows[from:to]
+
+		if _, err := h.execBatch(ctx, chunk, size); err == nil {
+			result.Succeeded += len(chunk)
+			continue
+		}
+		ctx.Warn("Batch chunk of rows %d to %d failed, repeating row by row", chunk[0].index, chunk[len(chunk)-1].index)
+		for _, row := range chunk {
+			if _, err := h.execBatch(ctx, []*batchRow{row}, 1); err != nil {
+				result.Errors = append(result.Errors, h.batchError(row.index, err))
+				continue
+			}
+			result.Succeeded++
+		}
+	}
+	return result
+}
+
+// batch prepares a row for every index and executes the prepared rows. Rows that could not be
+// prepared are reported as failed, all-or-nothing batches are not executed at all then.
+func (h *sqlHandler) batch(ctx golik.CloveContext, count int, mode BatchMode, size int, prepare func(int) (*batchRow, error)) *BatchResult {
+	rows := make([]*batchRow, 0, count)
+	errs := make([]*BatchError, 0)
+	for i := 0; i < count; i++ {
+		row, err := prepare(i)
+		if err != nil {
+			errs = append(errs, &BatchError{Index: i, Err: err})
+			if mode == BatchAllOrNothing {
+				return &BatchResult{Errors: errs}
+			}
+			continue
+		}
+		rows = append(rows, row)
+	}
+
+	result := h.runBatch(ctx, rows, mode, size)
+	result.Errors = append(errs, result.Errors...)
+	sort.Slice(result.Errors, func(i, j int) bool {
+		return result.Errors[i].Index < result.Errors[j].Index
+	})
+	return result
+}
+
+func (h *sqlHandler) batchCreate(ctx golik.CloveContext, cmd *BatchCreateCommand) *BatchResult {
+	return h.batch(ctx, len(cmd.Entities), cmd.Mode, cmd.ChunkSize, func(i int) (*batchRow, error) {
+		return h.createRow(ctx, i, cmd.Entities[i])
+	})
+}
+
+func (h *sqlHandler) batchUpdate(ctx golik.CloveContext, cmd *BatchUpdateCommand) *BatchResult {
+	return h.batch(ctx, len(cmd.Entities), cmd.Mode, cmd.ChunkSize, func(i int) (*batchRow, error) {
+		return h.updateRow(ctx, i, cmd.Entities[i])
+	})
+}
+
+func (h *sqlHandler) batchDelete(ctx golik.CloveContext, cmd *BatchDeleteCommand) *BatchResult {
+	return h.batch(ctx, len(cmd.Ids), cmd.Mode, cmd.ChunkSize, func(i int) (*batchRow, error) {
+		return h.deleteRow(i, cmd.Ids[i]), nil
+	})
+}
@@ -0,0 +1,97 @@
+package sql
+
+import (
+	"database/sql"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestBatchError(t *testing.T) {
+	if got := (&BatchError{Index: -1, Err: ErrConflict}).Error(); got != "Batch: "+ErrConflict.Error() {
+		t.Errorf("batch failure = %q", got)
+	}
+	err := &BatchError{Index: 3, Err: ErrConflict}
+	if got := err.Error(); got != "Row 3: "+ErrConflict.Error() {
+		t.Errorf("row failure = %q", got)
+	}
+	if !errors.Is(err, ErrConflict) {
+		t.Error("batch error must unwrap the failure of the row")
+	}
+
+	result := &BatchResult{}
+	if result.Err() != nil {
+		t.Error("expected no error without failures")
+	}
+	result.Errors = []*BatchError{err, {Index: 5, Err: ErrNotFound}}
+	if result.Err() != err {
+		t.Errorf("Err = %v, want the first failure", result.Err())
+	}
+}
+
+func TestMaxInsertRows(t *testing.T) {
+	tests := []struct {
+		dialect MultiRowInserter
+		columns int
+		want    int
+	}{
+		{DB2.(MultiRowInserter), 3, 10922},
+		{Postgres.(MultiRowInserter), 3, 21845},
+		{SQLite.(MultiRowInserter), 3, 333},
+		{SQLite.(MultiRowInserter), 1000, 1},
+		{MySQL.(MultiRowInserter), 0, 1},
+		{SQLServer.(MultiRowInserter), 1, 1000},
+		{SQLServer.(MultiRowInserter), 3, 699},
+	}
+
+	for _, tt := range tests {
+		if got := tt.dialect.MaxInsertRows(tt.columns); got != tt.want {
+			t.Errorf("%T.MaxInsertRows(%d) = %d, want %d", tt.dialect, tt.columns, got, tt.want)
+		}
+	}
+	if _, ok := Oracle.(MultiRowInserter); ok {
+		t.Error("oracle does not support several rows in the VALUES clause")
+	}
+}
+
+func TestBatchRows(t *testing.T) {
+	h, err := NewHandler(&HandlerSettings{Database: &sql.DB{}, Dialect: Postgres, Type: reflect.TypeOf(testItem{}), Table: "items", BatchSize: 50})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sh := h.(*sqlHandler)
+
+	if sh.batchSize(0) != 50 || sh.batchSize(10) != 10 {
+		t.Errorf("batch size = %d, %d, want 50, 10", sh.batchSize(0), sh.batchSize(10))
+	}
+
+	if _, err := sh.createRow(&testContext{}, 0, testItem{}); err == nil {
+		t.Error("expected error for entity of wrong type")
+	}
+
+	row, err := sh.createRow(&testContext{}, 0, &testItem{ID: 1, Name: "x"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "INSERT INTO items (ID, NAME) VALUES (?, ?), (?, ?), (?, ?)"; row.multi == nil || row.multi(3) != want {
+		t.Errorf("combined insert, want %q", want)
+	}
+	if !reflect.DeepEqual(row.args, []interface{}{int64(1), "x"}) {
+		t.Errorf("args = %v", row.args)
+	}
+
+	row, err = sh.updateRow(&testContext{}, 1, &testItem{ID: 1, Name: "y"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "UPDATE items SET NAME = ? WHERE ID = ?"; row.query != want {
+		t.Errorf("update = %q, want %q", row.query, want)
+	}
+	if !reflect.DeepEqual(row.args, []interface{}{"y", int64(1)}) {
+		t.Errorf("args = %v", row.args)
+	}
+
+	if want := "DELETE FROM items WHERE ID = ?"; sh.deleteRow(2, 1).query != want {
+		t.Errorf("delete = %q, want %q", sh.deleteRow(2, 1).query, want)
+	}
+}
@@ -0,0 +1,120 @@
+package sql
+
+import (
+	"github.com/ioswarm/golik"
+)
+
+// FilterCommand extends golik.Filter with options only supported by sql connection pools.
+// With Keyset set, pages are selected behind the given Cursor instead of by Filter.From
+// and the handler replies with a FilterResult.
+type FilterCommand struct {
+	Filter *golik.Filter
+	Sort   []Sort
+	Keyset bool
+	Cursor string
+	// Count overrides the count mode of the connection pool if set.
+	Count      *CountMode
+	CountLimit int
+	// IncludeDeleted includes soft deleted entities.
+	IncludeDeleted bool
+}
+
+func NewFilterCommand(filter *golik.Filter, sort ...Sort) *FilterCommand {
+	return &FilterCommand{
+		Filter: filter,
+		Sort:   sort,
+	}
+}
+
+// NewKeysetCommand filters the page behind the given cursor, an empty cursor selects the first page.
+func NewKeysetCommand(filter *golik.Filter, cursor string, sort ...Sort) *FilterCommand {
+	return &FilterCommand{
+		Filter: filter,
+		Sort:   sort,
+		Keyset: true,
+		Cursor: cursor,
+	}
+}
+
+// FilterResult is the reply of keyset filter commands.
+type FilterResult struct {
+	*golik.Result
+	// Cursor points behind the last entity of the result, it is empty on the last page.
+	Cursor string
+}
+
+// ReadCommand reads a single entity like golik.GetCommand, but may include soft deleted entities.
+type ReadCommand struct {
+	Id             interface{}
+	IncludeDeleted bool
+}
+
+// RestoreCommand marks a soft deleted entity as active again, the handler replies with the entity.
+type RestoreCommand struct {
+	Id interface{}
+}
+
+// PurgeCommand removes an entity from the table, even if soft delete is enabled.
+// The handler replies with the removed entity.
+type PurgeCommand struct {
+	Id interface{}
+}
+
+// UpsertCommand creates the entity or updates the existing entity with the same index field or
+// configured unique key in a single statement. The handler replies with the written entity.
+type UpsertCommand struct {
+	Entity interface{}
+}
+
+// PatchCommand updates only some fields of an entity, either the fields of Entity named
+// in Fields or the fields given in Values. Field names are go or column names, the
+// handler replies with the updated entity. If the version field of versioned entities
+// is part of the patch, the update is only applied if the version matches.
+type PatchCommand struct {
+	Id     interface{}
+	Entity interface{}
+	Fields []string
+	Values map[string]interface{}
+}
+
+// NewPatchCommand patches the given fields of entity.
+func NewPatchCommand(id interface{}, entity interface{}, fields ...string) *PatchCommand {
+	return &PatchCommand{
+		Id:     id,
+		Entity: entity,
+		Fields: fields,
+	}
+}
+
+// NewPatchValuesCommand patches the fields named by the keys of values.
+func NewPatchValuesCommand(id interface{}, values map[string]interface{}) *PatchCommand {
+	return &PatchCommand{
+		Id:     id,
+		Values: values,
+	}
+}
+
+// BatchCreateCommand creates all entities, which are pointers to the entity type. Rows are
+// combined into multi-row inserts if supported by the dialect. The handler replies with
+// a BatchResult.
+type BatchCreateCommand struct {
+	Entities []interface{}
+	Mode     BatchMode
+	// ChunkSize is the number of rows per transaction of best-effort batches
+	// and the maximum number of rows combined into one statement.
+	ChunkSize int
+}
+
+// BatchUpdateCommand updates all entities by their index field, the handler replies with a BatchResult.
+type BatchUpdateCommand struct {
+	Entities  []interface{}
+	Mode      BatchMode
+	ChunkSize int
+}
+
+// BatchDeleteCommand deletes the entities of all ids, the handler replies with a BatchResult.
+type BatchDeleteCommand struct {
+	Ids       []interface{}
+	Mode      BatchMode
+	ChunkSize int
+}
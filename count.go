@@ -0,0 +1,136 @@
+package sql
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/ioswarm/golik"
+)
+
+// CountMode defines how the total count of a filter result is determined.
+type CountMode int
+
+const (
+	// CountExact counts all matching rows.
+	CountExact CountMode = iota
+	// CountNone skips counting, the count of the result is -1.
+	CountNone
+	// CountCapped counts up to CountLimit+1 rows, a count above CountLimit means "more than CountLimit".
+	CountCapped
+	// CountEstimated uses the table statistics of the database for unfiltered results
+	// and falls back to an exact count otherwise.
+	CountEstimated
+)
+
+func ParseCountMode(mode string) (CountMode, error) {
+	switch strings.ToLower(mode) {
+	case "", "exact":
+		return CountExact, nil
+	case "none":
+		return CountNone, nil
+	case "capped":
+		return CountCapped, nil
+	case "estimated":
+		return CountEstimated, nil
+	default:
+		return CountExact, fmt.Errorf("Unknown count mode %v", mode)
+	}
+}
+
+// Estimator is implemented by dialects able to estimate the row count of a table from statistics.
+// The query returns a single negative or NULL value if no statistics are available, an empty
+// query means the dialect has no statistics at all.
+type Estimator interface {
+	EstimateCount(schema string, table string) (string, []interface{})
+}
+
+func (d *db2Dialect) EstimateCount(schema string, table string) (string, []interface{}) {
+	if schema == "" {
+		return "SELECT CARD FROM SYSCAT.TABLES WHERE TABSCHEMA = CURRENT SCHEMA AND TABNAME = ?", []interface{}{table}
+	}
+	return "SELECT CARD FROM SYSCAT.TABLES WHERE TABSCHEMA = ? AND TABNAME = ?", []interface{}{schema, table}
+}
+
+func (d *postgresDialect) EstimateCount(schema string, table string) (string, []interface{}) {
+	path := table
+	if schema != "" {
+		path = schema + "." + table
+	}
+	return "SELECT CAST(reltuples AS BIGINT) FROM pg_class WHERE oid = to_regclass(?)", []interface{}{path}
+}
+
+// EstimateCount returns no query, SQLite keeps no row counts the exact count could be replaced with.
+func (d *sqliteDialect) EstimateCount(schema string, table string) (string, []interface{}) {
+	return "", nil
+}
+
+func (d *mysqlDialect) EstimateCount(schema string, table string) (string, []interface{}) {
+	if schema == "" {
+		return "SELECT TABLE_ROWS FROM information_schema.TABLES WHERE TABLE_SCHEMA = DATABASE() AND TABLE_NAME = ?", []interface{}{table}
+	}
+	return "SELECT TABLE_ROWS FROM information_schema.TABLES WHERE TABLE_SCHEMA = ? AND TABLE_NAME = ?", []interface{}{schema, table}
+}
+
+func (h *sqlHandler) queryCount(c context.Context, ctx golik.CloveContext, qry string, args []interface{}) (int, bool, error) {
+	rows, err := h.query(c, ctx, qry, args...)
+	if err != nil {
+		return 0, false, err
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		return 0, false, rows.Err()
+	}
+	var result *int64
+	if err := rows.Scan(&result); err != nil {
+		return 0, false, err
+	}
+	if result == nil {
+		return 0, false, nil
+	}
+	return int(*result), true, nil
+}
+
+func (h *sqlHandler) count(c context.Context, ctx golik.CloveContext, mode CountMode, limit int, where string, args []interface{}) (int, error) {
+	switch mode {
+	case CountNone:
+		return -1, nil
+	case CountCapped:
+		inner := fmt.Sprintf("SELECT %v FROM %v %v", h.quote(h.indexCol), h.tablePath(), where)
+		qry, pageArgs := h.dialect.Paginate(h.quote(h.indexCol), inner, h.quote(h.indexCol), 0, limit+1)
+		result, _, err := h.queryCount(c, ctx, fmt.Sprintf("SELECT count(*) FROM (%v) c", qry), append(append([]interface{}{}, args...), pageArgs...))
+		return result, err
+	case CountEstimated:
+		if estimator, ok := h.dialect.(Estimator); ok && where == "" {
+			if qry, estArgs := estimator.EstimateCount(h.schema, h.table); qry != "" {
+				result, ok, err := h.queryCount(c, ctx, qry, estArgs)
+				if err != nil {
+					return 0, err
+				}
+				if ok && result >= 0 {
+					return result, nil
+				}
+			}
+		}
+	}
+
+	result, _, err := h.queryCount(c, ctx, "SELECT count(*) as cnt from "+h.tablePath()+" "+where, args)
+	return result, err
+}
+
+type countResult struct {
+	count int
+	err   error
+}
+
+// countAsync runs the count query concurrently to the page query of a filter.
+func (h *sqlHandler) countAsync(c context.Context, ctx golik.CloveContext, mode CountMode, limit int, where string, args []interface{}) <-chan countResult {
+	result := make(chan countResult, 1)
+	args = append([]interface{}{}, args...)
+	go func() {
+		count, err := h.count(c, ctx, mode, limit, where, args)
+		result <- countResult{count: count, err: err}
+	}()
+	return result
+}
@@ -0,0 +1,56 @@
+package sql
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseCountMode(t *testing.T) {
+	tests := []struct {
+		mode string
+		want CountMode
+		err  bool
+	}{
+		{mode: "", want: CountExact},
+		{mode: "Exact", want: CountExact},
+		{mode: "none", want: CountNone},
+		{mode: "capped", want: CountCapped},
+		{mode: "ESTIMATED", want: CountEstimated},
+		{mode: "guess", err: true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseCountMode(tt.mode)
+		if tt.err != (err != nil) || got != tt.want {
+			t.Errorf("ParseCountMode(%q) = %v, %v, want %v", tt.mode, got, err, tt.want)
+		}
+	}
+}
+
+func TestEstimateCount(t *testing.T) {
+	tests := []struct {
+		dialect Dialect
+		schema  string
+		want    string
+		args    []interface{}
+	}{
+		{DB2, "", "SELECT CARD FROM SYSCAT.TABLES WHERE TABSCHEMA = CURRENT SCHEMA AND TABNAME = ?", []interface{}{"users"}},
+		{DB2, "app", "SELECT CARD FROM SYSCAT.TABLES WHERE TABSCHEMA = ? AND TABNAME = ?", []interface{}{"app", "users"}},
+		{Postgres, "app", "SELECT CAST(reltuples AS BIGINT) FROM pg_class WHERE oid = to_regclass(?)", []interface{}{"app.users"}},
+		{MySQL, "", "SELECT TABLE_ROWS FROM information_schema.TABLES WHERE TABLE_SCHEMA = DATABASE() AND TABLE_NAME = ?", []interface{}{"users"}},
+		{SQLite, "", "", nil},
+	}
+
+	for _, tt := range tests {
+		qry, args := tt.dialect.(Estimator).EstimateCount(tt.schema, "users")
+		if qry != tt.want || !reflect.DeepEqual(args, tt.args) {
+			t.Errorf("%v EstimateCount = %q %v, want %q %v", tt.dialect.Name(), qry, args, tt.want, tt.args)
+		}
+	}
+
+	for _, d := range []Dialect{SQLServer, Oracle} {
+		if _, ok := d.(Estimator); ok {
+			t.Errorf("%v is not expected to estimate counts", d.Name())
+		}
+	}
+}
@@ -0,0 +1,388 @@
+package sql
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Dialect encapsulates everything that differs between the supported sql flavors.
+// Queries are always built with '?' placeholders and rebound via Rebind before execution.
+type Dialect interface {
+	Name() string
+	// Placeholder returns the bind parameter for the given 1-based argument index.
+	Placeholder(index int) string
+	Quote(identifier string) string
+	// Paginate wraps the given select query, so that only size rows starting at from are returned.
+	Paginate(columns string, query string, orderBy string, from int, size int) (string, []interface{})
+	// OrderBy renders a single term of an order by clause.
+	OrderBy(column string, descending bool, nulls NullsOrder) string
+	BoolLiteral(bool) string
+	TimeLiteral(time.Time) string
+	// Upsert returns an insert-or-update statement for the given columns keyed by keys, values are the
+	// parameters of the columns. On conflict the update columns are set to the inserted values and the
+	// increment columns are incremented.
+	Upsert(table string, columns []string, values []string, keys []string, update []string, increment []string) string
+}
+
+var (
+	dialectMutex sync.RWMutex
+	dialects     = map[string]Dialect{}
+)
+
+// RegisterDialect makes the given dialect available for the driver or dialect name.
+func RegisterDialect(name string, dialect Dialect) {
+	dialectMutex.Lock()
+	defer dialectMutex.Unlock()
+	dialects[strings.ToLower(name)] = dialect
+}
+
+// LookupDialect returns the dialect registered for the given driver or dialect name.
+func LookupDialect(name string) (Dialect, bool) {
+	dialectMutex.RLock()
+	defer dialectMutex.RUnlock()
+	d, ok := dialects[strings.ToLower(name)]
+	return d, ok
+}
+
+// Rebind replaces all '?' placeholders outside of quoted literals with the placeholders of the dialect.
+func Rebind(dialect Dialect, query string) string {
+	if dialect.Placeholder(1) == "?" {
+		return query
+	}
+
+	var sb strings.Builder
+	var quote rune
+	index := 0
+	for _, r := range query {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			}
+		case r == '\'' || r == '"':
+			quote = r
+		case r == '?':
+			index++
+			sb.WriteString(dialect.Placeholder(index))
+			continue
+		}
+		sb.WriteRune(r)
+	}
+	return sb.String()
+}
+
+func quoteIdentifier(identifier string, open string, close string) string {
+	parts := strings.Split(identifier, ".")
+	for i, part := range parts {
+		parts[i] = open + strings.ReplaceAll(part, close, close+close) + close
+	}
+	return strings.Join(parts, ".")
+}
+
+func updateAssignments(update []string, increment []string, assign func(string) string, current func(string) string) []string {
+	result := make([]string, 0, len(update)+len(increment))
+	for _, c := range update {
+		result = append(result, fmt.Sprintf("%v = %v", c, assign(c)))
+	}
+	for _, c := range increment {
+		result = append(result, fmt.Sprintf("%v = %v + 1", c, current(c)))
+	}
+	return result
+}
+
+func mergeUpsert(table string, source string, columns []string, keys []string, update []string, increment []string) string {
+	on := make([]string, len(keys))
+	for i, k := range keys {
+		on[i] = fmt.Sprintf("t.%v = s.%v", k, k)
+	}
+	values := make([]string, len(columns))
+	for i, c := range columns {
+		values[i] = "s." + c
+	}
+
+	qry := fmt.Sprintf("MERGE INTO %v t USING %v ON (%v)", table, source, strings.Join(on, " AND "))
+	set := updateAssignments(update, increment, func(c string) string { return "s." + c }, func(c string) string { return "t." + c })
+	if len(set) > 0 {
+		qry += " WHEN MATCHED THEN UPDATE SET " + strings.Join(set, ", ")
+	}
+	return qry + fmt.Sprintf(" WHEN NOT MATCHED THEN INSERT (%v) VALUES (%v)", strings.Join(columns, ", "), strings.Join(values, ", "))
+}
+
+var db2FilterQuery = `
+select %s from (
+  select * from (
+	select
+	  row_number() over (order by %s) as line_num,
+	  a.*
+	from (
+      %s
+	) a
+  ) x
+  where x.line_num between ? and ?
+) y
+order by y.line_num
+`
+
+type db2Dialect struct{}
+
+// DB2 is the dialect for IBM DB2 used with go_ibm_db.
+var DB2 Dialect = &db2Dialect{}
+
+func (d *db2Dialect) Name() string {
+	return "db2"
+}
+
+func (d *db2Dialect) Placeholder(index int) string {
+	return "?"
+}
+
+func (d *db2Dialect) Quote(identifier string) string {
+	return quoteIdentifier(identifier, `"`, `"`)
+}
+
+func (d *db2Dialect) Paginate(columns string, query string, orderBy string, from int, size int) (string, []interface{}) {
+	return fmt.Sprintf(db2FilterQuery, columns, orderBy, query), []interface{}{from + 1, from + size}
+}
+
+func (d *db2Dialect) OrderBy(column string, descending bool, nulls NullsOrder) string {
+	return nullsOrderTerm(column, descending, nulls)
+}
+
+func (d *db2Dialect) BoolLiteral(b bool) string {
+	if b {
+		return "1"
+	}
+	return "0"
+}
+
+func (d *db2Dialect) TimeLiteral(t time.Time) string {
+	return fmt.Sprintf("TIMESTAMP('%v')", t.Format("2006-01-02 15:04:05.000000"))
+}
+
+func (d *db2Dialect) Upsert(table string, columns []string, values []string, keys []string, update []string, increment []string) string {
+	return mergeUpsert(table, fmt.Sprintf("(VALUES (%v)) s (%v)", strings.Join(values, ", "), strings.Join(columns, ", ")), columns, keys, update, increment)
+}
+
+type postgresDialect struct{}
+
+// Postgres is the dialect for PostgreSQL.
+var Postgres Dialect = &postgresDialect{}
+
+func (d *postgresDialect) Name() string {
+	return "postgres"
+}
+
+func (d *postgresDialect) Placeholder(index int) string {
+	return fmt.Sprintf("$%d", index)
+}
+
+func (d *postgresDialect) Quote(identifier string) string {
+	return quoteIdentifier(identifier, `"`, `"`)
+}
+
+func (d *postgresDialect) Paginate(columns string, query string, orderBy string, from int, size int) (string, []interface{}) {
+	return fmt.Sprintf("%v ORDER BY %v LIMIT ? OFFSET ?", query, orderBy), []interface{}{size, from}
+}
+
+func (d *postgresDialect) OrderBy(column string, descending bool, nulls NullsOrder) string {
+	return nullsOrderTerm(column, descending, nulls)
+}
+
+func (d *postgresDialect) BoolLiteral(b bool) string {
+	if b {
+		return "TRUE"
+	}
+	return "FALSE"
+}
+
+func (d *postgresDialect) TimeLiteral(t time.Time) string {
+	return fmt.Sprintf("TIMESTAMP '%v'", t.Format("2006-01-02 15:04:05.000000"))
+}
+
+func (d *postgresDialect) Upsert(table string, columns []string, values []string, keys []string, update []string, increment []string) string {
+	qry := fmt.Sprintf("INSERT INTO %v AS t (%v) VALUES (%v) ON CONFLICT (%v)", table, strings.Join(columns, ", "), strings.Join(values, ", "), strings.Join(keys, ", "))
+	set := updateAssignments(update, increment, func(c string) string { return "EXCLUDED." + c }, func(c string) string { return "t." + c })
+	if len(set) == 0 {
+		return qry + " DO NOTHING"
+	}
+	return qry + " DO UPDATE SET " + strings.Join(set, ", ")
+}
+
+// sqliteDialect shares the syntax of postgres. RETURNING requires SQLite 3.35 or later,
+// so inserted and deleted rows are read with separate statements.
+type sqliteDialect struct {
+	postgresDialect
+}
+
+// SQLite is the dialect for SQLite.
+var SQLite Dialect = &sqliteDialect{}
+
+func (d *sqliteDialect) Name() string {
+	return "sqlite"
+}
+
+func (d *sqliteDialect) Placeholder(index int) string {
+	return "?"
+}
+
+func (d *sqliteDialect) BoolLiteral(b bool) string {
+	if b {
+		return "1"
+	}
+	return "0"
+}
+
+func (d *sqliteDialect) TimeLiteral(t time.Time) string {
+	return fmt.Sprintf("'%v'", t.Format("2006-01-02 15:04:05.000"))
+}
+
+type mysqlDialect struct{}
+
+// MySQL is the dialect for MySQL and MariaDB.
+var MySQL Dialect = &mysqlDialect{}
+
+func (d *mysqlDialect) Name() string {
+	return "mysql"
+}
+
+func (d *mysqlDialect) Placeholder(index int) string {
+	return "?"
+}
+
+func (d *mysqlDialect) Quote(identifier string) string {
+	return quoteIdentifier(identifier, "`", "`")
+}
+
+func (d *mysqlDialect) Paginate(columns string, query string, orderBy string, from int, size int) (string, []interface{}) {
+	return fmt.Sprintf("%v ORDER BY %v LIMIT ? OFFSET ?", query, orderBy), []interface{}{size, from}
+}
+
+func (d *mysqlDialect) OrderBy(column string, descending bool, nulls NullsOrder) string {
+	return emulatedNullsOrderTerm(column, descending, nulls)
+}
+
+func (d *mysqlDialect) BoolLiteral(b bool) string {
+	if b {
+		return "TRUE"
+	}
+	return "FALSE"
+}
+
+func (d *mysqlDialect) TimeLiteral(t time.Time) string {
+	return fmt.Sprintf("TIMESTAMP '%v'", t.Format("2006-01-02 15:04:05.000000"))
+}
+
+func (d *mysqlDialect) Upsert(table string, columns []string, values []string, keys []string, update []string, increment []string) string {
+	set := updateAssignments(update, increment, func(c string) string { return fmt.Sprintf("VALUES(%v)", c) }, func(c string) string { return c })
+	if len(set) == 0 {
+		set = []string{fmt.Sprintf("%v = %v", keys[0], keys[0])}
+	}
+	return fmt.Sprintf("INSERT INTO %v (%v) VALUES (%v) ON DUPLICATE KEY UPDATE %v", table, strings.Join(columns, ", "), strings.Join(values, ", "), strings.Join(set, ", "))
+}
+
+type sqlServerDialect struct{}
+
+// SQLServer is the dialect for Microsoft SQL Server.
+var SQLServer Dialect = &sqlServerDialect{}
+
+func (d *sqlServerDialect) Name() string {
+	return "sqlserver"
+}
+
+func (d *sqlServerDialect) Placeholder(index int) string {
+	return fmt.Sprintf("@p%d", index)
+}
+
+func (d *sqlServerDialect) Quote(identifier string) string {
+	return quoteIdentifier(identifier, "[", "]")
+}
+
+func (d *sqlServerDialect) Paginate(columns string, query string, orderBy string, from int, size int) (string, []interface{}) {
+	return fmt.Sprintf("%v ORDER BY %v OFFSET ? ROWS FETCH NEXT ? ROWS ONLY", query, orderBy), []interface{}{from, size}
+}
+
+func (d *sqlServerDialect) OrderBy(column string, descending bool, nulls NullsOrder) string {
+	return emulatedNullsOrderTerm(column, descending, nulls)
+}
+
+func (d *sqlServerDialect) BoolLiteral(b bool) string {
+	if b {
+		return "1"
+	}
+	return "0"
+}
+
+func (d *sqlServerDialect) TimeLiteral(t time.Time) string {
+	return fmt.Sprintf("CAST('%v' AS DATETIME2)", t.Format("2006-01-02 15:04:05.0000000"))
+}
+
+func (d *sqlServerDialect) Upsert(table string, columns []string, values []string, keys []string, update []string, increment []string) string {
+	return mergeUpsert(table, fmt.Sprintf("(VALUES (%v)) AS s (%v)", strings.Join(values, ", "), strings.Join(columns, ", ")), columns, keys, update, increment) + ";"
+}
+
+type oracleDialect struct{}
+
+// Oracle is the dialect for Oracle Database 12c and later.
+var Oracle Dialect = &oracleDialect{}
+
+func (d *oracleDialect) Name() string {
+	return "oracle"
+}
+
+func (d *oracleDialect) Placeholder(index int) string {
+	return fmt.Sprintf(":%d", index)
+}
+
+func (d *oracleDialect) Quote(identifier string) string {
+	return quoteIdentifier(identifier, `"`, `"`)
+}
+
+func (d *oracleDialect) Paginate(columns string, query string, orderBy string, from int, size int) (string, []interface{}) {
+	return fmt.Sprintf("%v ORDER BY %v OFFSET ? ROWS FETCH NEXT ? ROWS ONLY", query, orderBy), []interface{}{from, size}
+}
+
+func (d *oracleDialect) OrderBy(column string, descending bool, nulls NullsOrder) string {
+	return nullsOrderTerm(column, descending, nulls)
+}
+
+func (d *oracleDialect) BoolLiteral(b bool) string {
+	if b {
+		return "1"
+	}
+	return "0"
+}
+
+func (d *oracleDialect) TimeLiteral(t time.Time) string {
+	return fmt.Sprintf("TIMESTAMP '%v'", t.Format("2006-01-02 15:04:05.000000"))
+}
+
+func (d *oracleDialect) Upsert(table string, columns []string, values []string, keys []string, update []string, increment []string) string {
+	selects := make([]string, len(columns))
+	for i, c := range columns {
+		selects[i] = values[i] + " " + c
+	}
+	return mergeUpsert(table, fmt.Sprintf("(SELECT %v FROM dual) s", strings.Join(selects, ", ")), columns, keys, update, increment)
+}
+
+func init() {
+	for _, name := range []string{"db2", "go_ibm_db"} {
+		RegisterDialect(name, DB2)
+	}
+	for _, name := range []string{"postgres", "postgresql", "pgx"} {
+		RegisterDialect(name, Postgres)
+	}
+	for _, name := range []string{"mysql", "mariadb"} {
+		RegisterDialect(name, MySQL)
+	}
+	for _, name := range []string{"sqlite", "sqlite3"} {
+		RegisterDialect(name, SQLite)
+	}
+	for _, name := range []string{"sqlserver", "mssql"} {
+		RegisterDialect(name, SQLServer)
+	}
+	for _, name := range []string{"oracle", "godror", "oci8"} {
+		RegisterDialect(name, Oracle)
+	}
+}
@@ -0,0 +1,97 @@
+package sql
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestRebind(t *testing.T) {
+	tests := []struct {
+		name    string
+		dialect Dialect
+		query   string
+		want    string
+	}{
+		{"question marks kept", MySQL, "a = ? AND b = '?'", "a = ? AND b = '?'"},
+		{"numbered", Postgres, "a = ? AND b = ?", "a = $1 AND b = $2"},
+		{"string literal skipped", Postgres, "a = '?' AND b = ?", "a = '?' AND b = $1"},
+		{"escaped quote in literal", Postgres, "a = 'it''s ?' AND b = ?", "a = 'it''s ?' AND b = $1"},
+		{"quoted identifier skipped", Postgres, `"a?" = ? AND b = ?`, `"a?" = $1 AND b = $2`},
+		{"named", SQLServer, "a = ? OR b = '?' OR c = ?", "a = @p1 OR b = '?' OR c = @p2"},
+		{"oracle", Oracle, "a = ? OR b = ?", "a = :1 OR b = :2"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Rebind(tt.dialect, tt.query); got != tt.want {
+				t.Errorf("Rebind(%q) = %q, want %q", tt.query, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestQuote(t *testing.T) {
+	tests := []struct {
+		dialect    Dialect
+		identifier string
+		want       string
+	}{
+		{DB2, "app.user", `"app"."user"`},
+		{Postgres, `a"b`, `"a""b"`},
+		{SQLite, "user", `"user"`},
+		{MySQL, "app.order", "`app`.`order`"},
+		{SQLServer, "a]b", "[a]]b]"},
+		{Oracle, "user", `"user"`},
+	}
+
+	for _, tt := range tests {
+		if got := tt.dialect.Quote(tt.identifier); got != tt.want {
+			t.Errorf("%v Quote(%q) = %q, want %q", tt.dialect.Name(), tt.identifier, got, tt.want)
+		}
+	}
+}
+
+func TestPaginate(t *testing.T) {
+	tests := []struct {
+		dialect Dialect
+		want    string
+		args    []interface{}
+	}{
+		{Postgres, "SELECT * FROM t ORDER BY id ASC LIMIT ? OFFSET ?", []interface{}{10, 20}},
+		{SQLite, "SELECT * FROM t ORDER BY id ASC LIMIT ? OFFSET ?", []interface{}{10, 20}},
+		{MySQL, "SELECT * FROM t ORDER BY id ASC LIMIT ? OFFSET ?", []interface{}{10, 20}},
+		{SQLServer, "SELECT * FROM t ORDER BY id ASC OFFSET ? ROWS FETCH NEXT ? ROWS ONLY", []interface{}{20, 10}},
+		{Oracle, "SELECT * FROM t ORDER BY id ASC OFFSET ? ROWS FETCH NEXT ? ROWS ONLY", []interface{}{20, 10}},
+	}
+
+	for _, tt := range tests {
+		qry, args := tt.dialect.Paginate("*", "SELECT * FROM t", "id ASC", 20, 10)
+		if qry != tt.want {
+			t.Errorf("%v Paginate = %q, want %q", tt.dialect.Name(), qry, tt.want)
+		}
+		if !reflect.DeepEqual(args, tt.args) {
+			t.Errorf("%v Paginate args = %v, want %v", tt.dialect.Name(), args, tt.args)
+		}
+	}
+
+	// db2 numbers the rows, the arguments are the first and last row number
+	qry, args := DB2.Paginate("*", "SELECT * FROM t", "id ASC", 20, 10)
+	if !strings.Contains(qry, "SELECT * FROM t") || !strings.Contains(qry, "id ASC") {
+		t.Errorf("db2 Paginate = %q, missing query or order", qry)
+	}
+	if !reflect.DeepEqual(args, []interface{}{21, 30}) {
+		t.Errorf("db2 Paginate args = %v, want [21 30]", args)
+	}
+}
+
+func TestLookupDialect(t *testing.T) {
+	for name, want := range map[string]Dialect{"go_ibm_db": DB2, "PGX": Postgres, "sqlite3": SQLite, "mariadb": MySQL} {
+		if got, ok := LookupDialect(name); !ok || got != want {
+			t.Errorf("LookupDialect(%q) = %v, want %v", name, got, want.Name())
+		}
+	}
+	if _, ok := LookupDialect("unknown"); ok {
+		t.Error("expected unknown dialect")
+	}
+}
@@ -5,40 +5,147 @@ import (
 	"fmt"
 	"reflect"
 	"strings"
+	"sync"
 )
 
-type FieldConversionRule interface {
-	ValuePointer() interface{}
-	ConvertValue(interface{}) (reflect.Value, error)
-	CanConvert(ftype reflect.Type) bool
+// FieldTag describes the `sql:"column,options"` tag of a struct field.
+type FieldTag struct {
+	Column     string
+	Ignore     bool
+	PrimaryKey bool
+	ReadOnly   bool
+	OmitEmpty  bool
+	Nullable   bool
+	JSON       bool
+	Version    bool
+	Deleted    bool
+	Audit      string
+	Generated  bool
+	Inline     bool
+	Prefix     string
+	// Type is the SQL type of the column, written values are cast to it, e.g. type=VARCHAR(100).
+	// type=json stores the field as json like the json option.
+	Type string
+}
+
+func splitTag(tag string) []string {
+	result := make([]string, 0)
+	depth := 0
+	start := 0
+	for i, r := range tag {
+		switch r {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		case ',':
+			if depth == 0 {
+				result = append(result, strings.TrimSpace(tag[start:i]))
+				start = i + 1
+			}
+		}
+	}
+	return append(result, strings.TrimSpace(tag[start:]))
+}
+
+func ParseFieldTag(tag string) FieldTag {
+	result := FieldTag{}
+	if tag == "-" {
+		result.Ignore = true
+		return result
+	}
+
+	parts := splitTag(tag)
+	result.Column = parts[0]
+	for _, opt := range parts[1:] {
+		key, value := opt, ""
+		if i := strings.Index(opt, "="); i >= 0 {
+			key, value = opt[:i], opt[i+1:]
+		}
+		switch strings.ToLower(key) {
+		case "pk":
+			result.PrimaryKey = true
+		case "readonly":
+			result.ReadOnly = true
+		case "omitempty":
+			result.OmitEmpty = true
+		case "nullable":
+			result.Nullable = true
+		case "json":
+			result.JSON = true
+		case "version":
+			result.Version = true
+		case "deleted":
+			result.Deleted = true
+		case "audit":
+			result.Audit = value
+		case "generated", "autoincrement":
+			result.Generated = true
+		case "inline":
+			result.Inline = true
+		case "prefix":
+			result.Prefix = value
+		case "type":
+			result.Type = value
+		}
+	}
+	return result
 }
 
 type Field interface {
 	Name() string
 	SQLName() string
+	Tag() FieldTag
 	Field() reflect.StructField
 	ConversionRule() FieldConversionRule
+	Converter() FieldConverter
 }
 
 func NewField(field reflect.StructField, rule FieldConversionRule) Field {
-	return &fieldDef{
+	meta := fieldMeta{
 		field: field,
-		rule:  rule,
+		tag:   ParseFieldTag(field.Tag.Get("sql")),
+		name:  field.Name,
+	}
+	return newField(meta, rule, rule.Bind(field), UpperCase)
+}
+
+func newField(meta fieldMeta, rule FieldConversionRule, converter FieldConverter, naming NamingStrategy) Field {
+	column := meta.tag.Column
+	if column == "" {
+		column = naming.ColumnName(meta.field.Name)
+	}
+	return &fieldDef{
+		field:     meta.field,
+		name:      meta.name,
+		tag:       meta.tag,
+		rule:      rule,
+		converter: converter,
+		column:    meta.prefix + column,
 	}
 }
 
 type fieldDef struct {
-	field reflect.StructField
-	rule  FieldConversionRule
+	field     reflect.StructField
+	name      string
+	tag       FieldTag
+	rule      FieldConversionRule
+	converter FieldConverter
+	column    string
 }
 
+// Name returns the go name of the field, fields of inline structs are qualified
+// with the name of the struct field, e.g. Address.Street.
 func (f *fieldDef) Name() string {
-	return f.field.Name
+	return f.name
 }
 
 func (f *fieldDef) SQLName() string {
-	// TODO interpret tags
-	return strings.ToUpper(f.Name())
+	return f.column
+}
+
+func (f *fieldDef) Tag() FieldTag {
+	return f.tag
 }
 
 func (f *fieldDef) Field() reflect.StructField {
@@ -49,16 +156,26 @@ func (f *fieldDef) ConversionRule() FieldConversionRule {
 	return f.rule
 }
 
+func (f *fieldDef) Converter() FieldConverter {
+	return f.converter
+}
+
 type EntityBuilder interface {
 	Rules() []FieldConversionRule
 	AddRule(...FieldConversionRule) EntityBuilder
+	Naming() NamingStrategy
+	WithNaming(NamingStrategy) EntityBuilder
 	Fields(...string) []Field
+	Field(string) (Field, bool)
+	PrimaryKey() (Field, bool)
 	ScanList() []interface{}
 
 	Read([]interface{}, interface{}) error
 
 	ValueOf(interface{}, string) (interface{}, error)
-	Values(interface{}, ...string) []interface{}
+	Values(interface{}, ...string) ([]interface{}, error)
+	Writable(interface{}, ...string) []Field
+	ValuesOf(interface{}, []Field) ([]interface{}, error)
 
 	SqlNames(...string) []string
 	ColumnQueryStr() string
@@ -66,8 +183,12 @@ type EntityBuilder interface {
 
 func NewEntityBuilder(etype reflect.Type) EntityBuilder {
 	return &entityBuilder{
-		etype: etype,
+		etype:  etype,
+		naming: UpperCase,
 		rules: []FieldConversionRule{
+			NewNullTypeRule(),
+			NewScannerRule(),
+			NewJSONRule(),
 			NewStringRule(),
 			NewTimeRule(),
 			NewIntRule(),
@@ -89,8 +210,13 @@ func NewEntityBuilder(etype reflect.Type) EntityBuilder {
 }
 
 type entityBuilder struct {
-	etype reflect.Type
-	rules []FieldConversionRule
+	etype  reflect.Type
+	naming NamingStrategy
+	rules  []FieldConversionRule
+
+	mutex    sync.RWMutex
+	fields   []Field
+	excluded map[string][]Field
 }
 
 func (eb *entityBuilder) Rules() []FieldConversionRule {
@@ -98,10 +224,30 @@ func (eb *entityBuilder) Rules() []FieldConversionRule {
 }
 
 func (eb *entityBuilder) AddRule(rule ...FieldConversionRule) EntityBuilder {
+	eb.mutex.Lock()
+	defer eb.mutex.Unlock()
 	eb.rules = append(rule, eb.rules...)
+	eb.reset()
+	return eb
+}
+
+func (eb *entityBuilder) Naming() NamingStrategy {
+	return eb.naming
+}
+
+func (eb *entityBuilder) WithNaming(naming NamingStrategy) EntityBuilder {
+	eb.mutex.Lock()
+	defer eb.mutex.Unlock()
+	eb.naming = naming
+	eb.reset()
 	return eb
 }
 
+func (eb *entityBuilder) reset() {
+	eb.fields = nil
+	eb.excluded = nil
+}
+
 func (eb *entityBuilder) findRuleMatch(ftype reflect.Type) (FieldConversionRule, bool) {
 	for _, rule := range eb.rules {
 		if rule.CanConvert(ftype) {
@@ -111,39 +257,144 @@ func (eb *entityBuilder) findRuleMatch(ftype reflect.Type) (FieldConversionRule,
 	return nil, false
 }
 
-func (eb *entityBuilder) Fields(expects ...string) []Field {
+// bind creates the converter of the given field. Fields tagged as json are always stored
+// as json, pointer fields are handled by the rule of their element type unless a rule
+// accepts the pointer type itself.
+func (eb *entityBuilder) bind(field reflect.StructField, tag FieldTag) (FieldConversionRule, FieldConverter, bool) {
+	if tag.JSON || strings.EqualFold(tag.Type, "json") {
+		rule := NewJSONRule()
+		return rule, rule.Bind(field), true
+	}
+	if rule, ok := eb.findRuleMatch(field.Type); ok {
+		return rule, rule.Bind(field), true
+	}
+	if field.Type.Kind() == reflect.Ptr {
+		if rule, ok := eb.findRuleMatch(field.Type.Elem()); ok {
+			elem := field
+			elem.Type = field.Type.Elem()
+			return rule, newPointerConverter(field.Type, rule.Bind(elem)), true
+		}
+	}
+	return nil, nil, false
+}
+
+func (eb *entityBuilder) resolveFields() []Field {
 	result := make([]Field, 0)
-	contains := func(name string) bool {
+	for _, meta := range structFields(eb.etype) {
+		if rule, converter, ok := eb.bind(meta.field, meta.tag); ok {
+			result = append(result, newField(meta, rule, converter, eb.naming))
+		}
+	}
+	return result
+}
+
+// Fields returns the mapped fields of the entity type without the expected ones.
+// The result is cached per set of expected names and must not be modified.
+func (eb *entityBuilder) Fields(expects ...string) []Field {
+	key := strings.ToUpper(strings.Join(expects, ","))
+
+	eb.mutex.RLock()
+	result, ok := eb.excluded[key]
+	eb.mutex.RUnlock()
+	if ok {
+		return result
+	}
+
+	eb.mutex.Lock()
+	defer eb.mutex.Unlock()
+	if eb.fields == nil {
+		eb.fields = eb.resolveFields()
+		eb.excluded = map[string][]Field{"": eb.fields}
+	}
+	if result, ok := eb.excluded[key]; ok {
+		return result
+	}
+
+	contains := func(f Field) bool {
 		for _, e := range expects {
-			if strings.ToUpper(e) == strings.ToUpper(name) {
+			if matchesField(f, e) {
 				return true
 			}
 		}
 		return false
 	}
 
-	for i := 0; i < eb.etype.NumField(); i++ {
-		field := eb.etype.Field(i)
-		name := []rune(field.Name)
-		if name[0] >= 65 && name[0] <= 90 {
-			if rule, ok := eb.findRuleMatch(field.Type); ok {
-				f := NewField(field, rule)
-				if !contains(f.SQLName()) {
-					result = append(result, f)
-				}
-			}
+	result = make([]Field, 0, len(eb.fields))
+	for _, f := range eb.fields {
+		if !contains(f) {
+			result = append(result, f)
 		}
 	}
+	eb.excluded[key] = result
 
 	return result
 }
 
+func matchesField(f Field, name string) bool {
+	return strings.EqualFold(f.Name(), name) || strings.EqualFold(f.SQLName(), name)
+}
+
+// lookupFields returns the fields configured by names or, if no names are configured, all
+// fields whose tag matches. Kind describes the fields in errors about unknown names.
+func lookupFields(builder EntityBuilder, kind string, names []string, tagged func(FieldTag) bool) ([]Field, error) {
+	result := make([]Field, 0)
+	if len(names) > 0 {
+		for _, name := range names {
+			fld, ok := builder.Field(name)
+			if !ok {
+				return nil, fmt.Errorf("Unknown %v field %v", kind, name)
+			}
+			result = append(result, fld)
+		}
+		return result, nil
+	}
+
+	for _, fld := range builder.Fields() {
+		if tagged(fld.Tag()) {
+			result = append(result, fld)
+		}
+	}
+	return result, nil
+}
+
+// lookupField returns the single field configured by name or the first field whose tag matches,
+// nil if there is none.
+func lookupField(builder EntityBuilder, kind string, name string, tagged func(FieldTag) bool) (Field, error) {
+	names := make([]string, 0, 1)
+	if name != "" {
+		names = append(names, name)
+	}
+	flds, err := lookupFields(builder, kind, names, tagged)
+	if err != nil || len(flds) == 0 {
+		return nil, err
+	}
+	return flds[0], nil
+}
+
+func (eb *entityBuilder) Field(name string) (Field, bool) {
+	for _, fld := range eb.Fields() {
+		if matchesField(fld, name) {
+			return fld, true
+		}
+	}
+	return nil, false
+}
+
+func (eb *entityBuilder) PrimaryKey() (Field, bool) {
+	for _, fld := range eb.Fields() {
+		if fld.Tag().PrimaryKey {
+			return fld, true
+		}
+	}
+	return nil, false
+}
+
 func (eb *entityBuilder) ScanList() []interface{} {
 	fields := eb.Fields()
 	result := make([]interface{}, len(fields))
 
 	for i, fld := range fields {
-		result[i] = fld.ConversionRule().ValuePointer()
+		result[i] = fld.Converter().ValuePointer()
 	}
 
 	return result
@@ -163,9 +414,9 @@ func (eb *entityBuilder) Read(values []interface{}, i interface{}) error {
 	elemvalue := ivalue.Elem()
 
 	for i, fld := range fields {
-		fldvalue := elemvalue.FieldByName(fld.Name())
+		fldvalue, _ := fieldByIndex(elemvalue, fld.Field().Index, true)
 		val := values[i]
-		vvalue, err := fld.ConversionRule().ConvertValue(val)
+		vvalue, err := fld.Converter().ConvertValue(val)
 		if err != nil {
 			return err
 		}
@@ -181,27 +432,61 @@ func (eb *entityBuilder) ValueOf(i interface{}, name string) (interface{}, error
 	elemvalue := ivalue.Elem()
 
 	for _, fld := range fields {
-		if strings.ToUpper(fld.Name()) == strings.ToUpper(name) {
-			fldvalue := elemvalue.FieldByName(fld.Name())
+		if matchesField(fld, name) {
+			fldvalue, ok := fieldByIndex(elemvalue, fld.Field().Index, false)
+			if !ok {
+				return nil, nil
+			}
 			return fldvalue.Interface(), nil
 		}
 	}
 	return nil, fmt.Errorf("Unknown field %v", name)
 }
 
-func (eb *entityBuilder) Values(i interface{}, expects ...string) []interface{} {
-	fields := eb.Fields(expects...)
+func (eb *entityBuilder) Values(i interface{}, expects ...string) ([]interface{}, error) {
+	return eb.ValuesOf(i, eb.Fields(expects...))
+}
+
+// Writable returns the fields that are written on insert or update of the given entity,
+// skipping readonly fields and omitempty fields holding their zero value.
+func (eb *entityBuilder) Writable(i interface{}, expects ...string) []Field {
+	elemvalue := reflect.ValueOf(i).Elem()
+	result := make([]Field, 0)
+	for _, fld := range eb.Fields(expects...) {
+		tag := fld.Tag()
+		if tag.ReadOnly {
+			continue
+		}
+		if tag.OmitEmpty {
+			if fldvalue, ok := fieldByIndex(elemvalue, fld.Field().Index, false); !ok || fldvalue.IsZero() {
+				continue
+			}
+		}
+		result = append(result, fld)
+	}
+	return result
+}
+
+func (eb *entityBuilder) ValuesOf(i interface{}, fields []Field) ([]interface{}, error) {
 	result := make([]interface{}, len(fields))
 
 	ivalue := reflect.ValueOf(i)
 	elemvalue := ivalue.Elem()
 
 	for i, fld := range fields {
-		fldvalue := elemvalue.FieldByName(fld.Name())
-		result[i] = fldvalue.Interface()
+		fldvalue, ok := fieldByIndex(elemvalue, fld.Field().Index, false)
+		if !ok || (fld.Tag().Nullable && fldvalue.IsZero()) {
+			result[i] = nil
+			continue
+		}
+		val, err := fld.Converter().DriverValue(fldvalue)
+		if err != nil {
+			return nil, err
+		}
+		result[i] = val
 	}
 
-	return result
+	return result, nil
 }
 
 func (eb *entityBuilder) SqlNames(expects ...string) []string {
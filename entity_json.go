@@ -0,0 +1,83 @@
+package sql
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+var jsonTypes = struct {
+	sync.RWMutex
+	types map[reflect.Type]bool
+}{types: make(map[reflect.Type]bool)}
+
+// RegisterJSONType stores all fields of the given types as json, without the need of a
+// `sql:",json"` tag. Types must be registered before the first entity builder uses them.
+func RegisterJSONType(types ...reflect.Type) {
+	jsonTypes.Lock()
+	defer jsonTypes.Unlock()
+	for _, t := range types {
+		jsonTypes.types[t] = true
+	}
+}
+
+func isJSONType(ftype reflect.Type) bool {
+	jsonTypes.RLock()
+	defer jsonTypes.RUnlock()
+	return jsonTypes.types[ftype]
+}
+
+// NewJSONRule handles fields of registered json types. Fields tagged with `sql:",json"`
+// are bound to this rule regardless of their type.
+func NewJSONRule() FieldConversionRule {
+	return &jsonRule{}
+}
+
+type jsonRule struct{}
+
+func (r *jsonRule) CanConvert(ftype reflect.Type) bool {
+	return isJSONType(ftype)
+}
+
+func (r *jsonRule) Bind(field reflect.StructField) FieldConverter {
+	return &jsonConverter{ftype: field.Type}
+}
+
+type jsonConverter struct {
+	ftype reflect.Type
+}
+
+func (c *jsonConverter) ValuePointer() interface{} {
+	return new(sql.NullString)
+}
+
+func (c *jsonConverter) ConvertValue(v interface{}) (reflect.Value, error) {
+	if isNull(v) {
+		return reflect.Zero(c.ftype), nil
+	}
+	val, ok := v.(*sql.NullString)
+	if !ok {
+		return reflect.ValueOf(nil), fmt.Errorf("Could not convert %T as json", v)
+	}
+	result := reflect.New(c.ftype)
+	if err := json.Unmarshal([]byte(val.String), result.Interface()); err != nil {
+		return reflect.ValueOf(nil), fmt.Errorf("Could not unmarshal json as %v: %v", c.ftype, err)
+	}
+	return result.Elem(), nil
+}
+
+func (c *jsonConverter) DriverValue(v reflect.Value) (interface{}, error) {
+	switch v.Kind() {
+	case reflect.Map, reflect.Slice, reflect.Ptr, reflect.Interface:
+		if v.IsNil() {
+			return nil, nil
+		}
+	}
+	data, err := json.Marshal(v.Interface())
+	if err != nil {
+		return nil, fmt.Errorf("Could not marshal %v as json: %v", c.ftype, err)
+	}
+	return string(data), nil
+}
@@ -0,0 +1,52 @@
+package sql
+
+import (
+	"reflect"
+	"testing"
+)
+
+type testSettings struct {
+	Theme string `json:"theme"`
+	Size  int    `json:"size"`
+}
+
+type testRegistered struct {
+	Enabled bool
+}
+
+type testDocument struct {
+	Settings   testSettings      `sql:",json"`
+	Optional   *testSettings     `sql:",json"`
+	Labels     map[string]string `sql:",json"`
+	Tags       []string          `sql:",json"`
+	Registered testRegistered
+}
+
+func TestJSONRoundTrip(t *testing.T) {
+	RegisterJSONType(reflect.TypeOf(testRegistered{}))
+
+	tests := []*testDocument{
+		{},
+		{
+			Settings:   testSettings{Theme: "dark", Size: 2},
+			Optional:   &testSettings{Theme: "light"},
+			Labels:     map[string]string{"a": "b"},
+			Tags:       []string{"x", "y"},
+			Registered: testRegistered{Enabled: true},
+		},
+	}
+	for _, entity := range tests {
+		if got := roundTrip(t, entity); !reflect.DeepEqual(got, entity) {
+			t.Errorf("round trip = %+v, want %+v", got, entity)
+		}
+	}
+
+	builder := NewEntityBuilder(reflect.TypeOf(testDocument{}))
+	vals, err := builder.ValuesOf(&testDocument{Settings: testSettings{Theme: "dark"}}, builder.Fields())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []interface{}{`{"theme":"dark","size":0}`, nil, nil, nil, `{"Enabled":false}`}; !reflect.DeepEqual(vals, want) {
+		t.Errorf("values = %#v, want %#v", vals, want)
+	}
+}
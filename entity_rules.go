@@ -1,6 +1,7 @@
 package sql
 
 import (
+	"database/sql/driver"
 	"fmt"
 	"reflect"
 	"time"
@@ -9,451 +10,415 @@ import (
 )
 
 var (
-	timetype = reflect.TypeOf(time.Time{})
+	timetype   = reflect.TypeOf(time.Time{})
+	stringtype = reflect.TypeOf("")
+
+	nulltypes = []reflect.Type{
+		reflect.TypeOf(sql.NullString{}),
+		reflect.TypeOf(sql.NullInt32{}),
+		reflect.TypeOf(sql.NullInt64{}),
+		reflect.TypeOf(sql.NullFloat64{}),
+		reflect.TypeOf(sql.NullBool{}),
+		reflect.TypeOf(sql.NullTime{}),
+	}
 )
 
-func NewStringRule() FieldConversionRule {
-	return &stringRule{}
+// FieldConversionRule decides which field types it handles and creates a
+// converter for every matching field. Rules must not keep per-field state.
+type FieldConversionRule interface {
+	CanConvert(ftype reflect.Type) bool
+	Bind(field reflect.StructField) FieldConverter
 }
 
-type stringRule struct{}
-
-func (r *stringRule) ValuePointer() interface{} {
-	return new(sql.NullString)
+// FieldConverter converts the values of a single field between go and the database.
+type FieldConverter interface {
+	// ValuePointer returns a fresh scan destination for the column of the field.
+	ValuePointer() interface{}
+	// ConvertValue converts a scanned value to a value assignable to the field.
+	ConvertValue(interface{}) (reflect.Value, error)
+	// DriverValue converts the field value to a value passed to the database.
+	DriverValue(reflect.Value) (interface{}, error)
 }
 
-func (r *stringRule) ConvertValue(v interface{}) (reflect.Value, error) {
+func isNull(v interface{}) bool {
 	if v == nil {
-		return reflect.ValueOf(""), nil
+		return true
 	}
-	if val, ok := v.(*sql.NullString); ok {
-		if val.Valid {
-			return reflect.ValueOf(val.String), nil
-		}
-		return reflect.ValueOf(""), nil
+	switch val := v.(type) {
+	case driver.Valuer:
+		dv, err := val.Value()
+		return err == nil && dv == nil
+	case *[]byte:
+		return val == nil || *val == nil
 	}
-
-	return reflect.ValueOf(nil), fmt.Errorf("Could not convert %T as string", v)
-}
-
-func (r *stringRule) CanConvert(ftype reflect.Type) bool {
-	return ftype.Kind() == reflect.String
+	return false
 }
 
-func NewTimeRule() FieldConversionRule {
-	return &timeRule{}
+func newPointerConverter(ftype reflect.Type, inner FieldConverter) FieldConverter {
+	return &pointerConverter{
+		ftype: ftype,
+		inner: inner,
+	}
 }
 
-type timeRule struct {
-	pointer bool
+// pointerConverter maps NULL to a nil pointer and delegates all other values
+// to the converter of the element type.
+type pointerConverter struct {
+	ftype reflect.Type
+	inner FieldConverter
 }
 
-func (r *timeRule) ValuePointer() interface{} {
-	return new(sql.NullTime)
+func (c *pointerConverter) ValuePointer() interface{} {
+	return c.inner.ValuePointer()
 }
 
-func (r *timeRule) ConvertValue(v interface{}) (reflect.Value, error) {
-	if v == nil {
-		if r.pointer {
-			return reflect.New(timetype), nil
-		}
-		return reflect.ValueOf(time.Time{}), nil
+func (c *pointerConverter) ConvertValue(v interface{}) (reflect.Value, error) {
+	if isNull(v) {
+		return reflect.Zero(c.ftype), nil
 	}
-	if val, ok := v.(*sql.NullTime); ok {
-		if val.Valid {
-			if r.pointer {
-				return reflect.ValueOf(&val.Time), nil
-			}
-			return reflect.ValueOf(val.Time), nil
-		}
-
-		if r.pointer {
-			return reflect.New(timetype), nil
-		}
-		return reflect.ValueOf(time.Time{}), nil
+	val, err := c.inner.ConvertValue(v)
+	if err != nil {
+		return reflect.ValueOf(nil), err
 	}
-	return reflect.ValueOf(nil), fmt.Errorf("Could not convert %T as int", v)
+	ptr := reflect.New(c.ftype.Elem())
+	ptr.Elem().Set(val)
+	return ptr, nil
 }
 
-func (r *timeRule) CanConvert(ftype reflect.Type) bool {
-	if ftype.Kind() == reflect.Ptr && ftype.Elem() == timetype {
-		r.pointer = true
-		return true
+func (c *pointerConverter) DriverValue(v reflect.Value) (interface{}, error) {
+	if v.IsNil() {
+		return nil, nil
 	}
-	return ftype == timetype
+	return c.inner.DriverValue(v.Elem())
 }
 
-func NewIntRule() FieldConversionRule {
-	return &intRule{}
+// scalarRule handles a single scalar kind scanned via one of the sql.Null* types.
+type scalarRule struct {
+	name    string
+	accept  func(reflect.Type) bool
+	pointer func() interface{}
+	value   func(interface{}) (value interface{}, valid bool, ok bool)
 }
 
-type intRule struct{}
-
-func (r *intRule) ValuePointer() interface{} {
-	return new(sql.NullInt32)
+func (r *scalarRule) CanConvert(ftype reflect.Type) bool {
+	return r.accept(ftype)
 }
 
-func (r *intRule) ConvertValue(v interface{}) (reflect.Value, error) {
-	if v == nil {
-		return reflect.ValueOf(0), nil
+func (r *scalarRule) Bind(field reflect.StructField) FieldConverter {
+	return &scalarConverter{
+		rule:  r,
+		ftype: field.Type,
 	}
-	if val, ok := v.(*sql.NullInt32); ok {
-		if val.Valid {
-			return reflect.ValueOf(int(val.Int32)), nil
-		}
-		return reflect.ValueOf(0), nil
-	}
-	return reflect.ValueOf(nil), fmt.Errorf("Could not convert %T as int", v)
 }
 
-func (r *intRule) CanConvert(ftype reflect.Type) bool {
-	return ftype.Kind() == reflect.Int
+type scalarConverter struct {
+	rule  *scalarRule
+	ftype reflect.Type
 }
 
-func NewInt8Rule() FieldConversionRule {
-	return &int8Rule{}
+func (c *scalarConverter) ValuePointer() interface{} {
+	return c.rule.pointer()
 }
 
-type int8Rule struct{}
-
-func (r *int8Rule) ValuePointer() interface{} {
-	return new(sql.NullInt32)
-}
-
-func (r *int8Rule) ConvertValue(v interface{}) (reflect.Value, error) {
+func (c *scalarConverter) ConvertValue(v interface{}) (reflect.Value, error) {
 	if v == nil {
-		return reflect.ValueOf(int8(0)), nil
+		return reflect.Zero(c.ftype), nil
 	}
-	if val, ok := v.(*sql.NullInt32); ok {
-		if val.Valid {
-			return reflect.ValueOf(int8(val.Int32)), nil
-		}
-		return reflect.ValueOf(int8(0)), nil
+	val, valid, ok := c.rule.value(v)
+	if !ok {
+		return reflect.ValueOf(nil), fmt.Errorf("Could not convert %T as %v", v, c.rule.name)
 	}
-	return reflect.ValueOf(nil), fmt.Errorf("Could not convert %T as int", v)
-}
-
-func (r *int8Rule) CanConvert(ftype reflect.Type) bool {
-	return ftype.Kind() == reflect.Int8
+	if !valid {
+		return reflect.Zero(c.ftype), nil
+	}
+	return reflect.ValueOf(val).Convert(c.ftype), nil
 }
 
-func NewInt16Rule() FieldConversionRule {
-	return &int16Rule{}
+func (c *scalarConverter) DriverValue(v reflect.Value) (interface{}, error) {
+	return v.Interface(), nil
 }
 
-type int16Rule struct{}
-
-func (r *int16Rule) ValuePointer() interface{} {
-	return new(sql.NullInt32)
+func kindOf(kind reflect.Kind) func(reflect.Type) bool {
+	return func(ftype reflect.Type) bool {
+		return ftype.Kind() == kind
+	}
 }
 
-func (r *int16Rule) ConvertValue(v interface{}) (reflect.Value, error) {
-	if v == nil {
-		return reflect.ValueOf(int16(0)), nil
-	}
-	if val, ok := v.(*sql.NullInt32); ok {
-		if val.Valid {
-			return reflect.ValueOf(int16(val.Int32)), nil
-		}
-		return reflect.ValueOf(int16(0)), nil
+func newNullInt32Rule(kind reflect.Kind) FieldConversionRule {
+	return &scalarRule{
+		name:    kind.String(),
+		accept:  kindOf(kind),
+		pointer: func() interface{} { return new(sql.NullInt32) },
+		value: func(v interface{}) (interface{}, bool, bool) {
+			val, ok := v.(*sql.NullInt32)
+			if !ok {
+				return nil, false, false
+			}
+			return val.Int32, val.Valid, true
+		},
 	}
-	return reflect.ValueOf(nil), fmt.Errorf("Could not convert %T as int", v)
 }
 
-func (r *int16Rule) CanConvert(ftype reflect.Type) bool {
-	return ftype.Kind() == reflect.Int16
+func newNullInt64Rule(kind reflect.Kind) FieldConversionRule {
+	return &scalarRule{
+		name:    kind.String(),
+		accept:  kindOf(kind),
+		pointer: func() interface{} { return new(sql.NullInt64) },
+		value: func(v interface{}) (interface{}, bool, bool) {
+			val, ok := v.(*sql.NullInt64)
+			if !ok {
+				return nil, false, false
+			}
+			return val.Int64, val.Valid, true
+		},
+	}
 }
 
-func NewInt32Rule() FieldConversionRule {
-	return &int32Rule{}
+func newNullFloat64Rule(kind reflect.Kind) FieldConversionRule {
+	return &scalarRule{
+		name:    kind.String(),
+		accept:  kindOf(kind),
+		pointer: func() interface{} { return new(sql.NullFloat64) },
+		value: func(v interface{}) (interface{}, bool, bool) {
+			val, ok := v.(*sql.NullFloat64)
+			if !ok {
+				return nil, false, false
+			}
+			return val.Float64, val.Valid, true
+		},
+	}
 }
 
-type int32Rule struct{}
-
-func (r *int32Rule) ValuePointer() interface{} {
-	return new(sql.NullInt32)
+func NewStringRule() FieldConversionRule {
+	return &scalarRule{
+		name:    "string",
+		accept:  kindOf(reflect.String),
+		pointer: func() interface{} { return new(sql.NullString) },
+		value: func(v interface{}) (interface{}, bool, bool) {
+			val, ok := v.(*sql.NullString)
+			if !ok {
+				return nil, false, false
+			}
+			return val.String, val.Valid, true
+		},
+	}
 }
 
-func (r *int32Rule) ConvertValue(v interface{}) (reflect.Value, error) {
-	if v == nil {
-		return reflect.ValueOf(int32(0)), nil
-	}
-	if val, ok := v.(*sql.NullInt32); ok {
-		if val.Valid {
-			return reflect.ValueOf(val.Int32), nil
-		}
-		return reflect.ValueOf(int32(0)), nil
+func NewTimeRule() FieldConversionRule {
+	return &scalarRule{
+		name:    "time",
+		accept:  func(ftype reflect.Type) bool { return ftype == timetype },
+		pointer: func() interface{} { return new(sql.NullTime) },
+		value: func(v interface{}) (interface{}, bool, bool) {
+			val, ok := v.(*sql.NullTime)
+			if !ok {
+				return nil, false, false
+			}
+			return val.Time, val.Valid, true
+		},
 	}
-	return reflect.ValueOf(nil), fmt.Errorf("Could not convert %T as int", v)
 }
 
-func (r *int32Rule) CanConvert(ftype reflect.Type) bool {
-	return ftype.Kind() == reflect.Int32
+func NewIntRule() FieldConversionRule {
+	return newNullInt64Rule(reflect.Int)
 }
 
-func NewInt64Rule() FieldConversionRule {
-	return &int64Rule{}
+func NewInt8Rule() FieldConversionRule {
+	return newNullInt32Rule(reflect.Int8)
 }
 
-type int64Rule struct{}
-
-func (r *int64Rule) ValuePointer() interface{} {
-	return new(sql.NullInt64)
+func NewInt16Rule() FieldConversionRule {
+	return newNullInt32Rule(reflect.Int16)
 }
 
-func (r *int64Rule) ConvertValue(v interface{}) (reflect.Value, error) {
-	if v == nil {
-		return reflect.ValueOf(int64(0)), nil
-	}
-	if val, ok := v.(*sql.NullInt64); ok {
-		if val.Valid {
-			return reflect.ValueOf(val.Int64), nil
-		}
-		return reflect.ValueOf(int64(0)), nil
-	}
-	return reflect.ValueOf(nil), fmt.Errorf("Could not convert %T as int", v)
+func NewInt32Rule() FieldConversionRule {
+	return newNullInt32Rule(reflect.Int32)
 }
 
-func (r *int64Rule) CanConvert(ftype reflect.Type) bool {
-	return ftype.Kind() == reflect.Int64
+func NewInt64Rule() FieldConversionRule {
+	return newNullInt64Rule(reflect.Int64)
 }
 
 func NewUintRule() FieldConversionRule {
-	return &uintRule{}
-}
-
-type uintRule struct{}
-
-func (r *uintRule) ValuePointer() interface{} {
-	return new(sql.NullInt32)
-}
-
-func (r *uintRule) ConvertValue(v interface{}) (reflect.Value, error) {
-	if v == nil {
-		return reflect.ValueOf(uint(0)), nil
-	}
-	if val, ok := v.(*sql.NullInt32); ok {
-		if val.Valid {
-			return reflect.ValueOf(uint(val.Int32)), nil
-		}
-		return reflect.ValueOf(uint(0)), nil
-	}
-	return reflect.ValueOf(nil), fmt.Errorf("Could not convert %T as int", v)
-}
-
-func (r *uintRule) CanConvert(ftype reflect.Type) bool {
-	return ftype.Kind() == reflect.Uint
+	return newNullInt64Rule(reflect.Uint)
 }
 
 func NewUint8Rule() FieldConversionRule {
-	return &uint8Rule{}
+	return newNullInt32Rule(reflect.Uint8)
 }
 
-type uint8Rule struct{}
-
-func (r *uint8Rule) ValuePointer() interface{} {
-	return new(sql.NullInt32)
+func NewUint16Rule() FieldConversionRule {
+	return newNullInt32Rule(reflect.Uint16)
 }
 
-func (r *uint8Rule) ConvertValue(v interface{}) (reflect.Value, error) {
-	if v == nil {
-		return reflect.ValueOf(uint8(0)), nil
-	}
-	if val, ok := v.(*sql.NullInt32); ok {
-		if val.Valid {
-			return reflect.ValueOf(uint8(val.Int32)), nil
-		}
-		return reflect.ValueOf(uint8(0)), nil
-	}
-	return reflect.ValueOf(nil), fmt.Errorf("Could not convert %T as int", v)
+func NewUint32Rule() FieldConversionRule {
+	return newNullInt64Rule(reflect.Uint32)
 }
 
-func (r *uint8Rule) CanConvert(ftype reflect.Type) bool {
-	return ftype.Kind() == reflect.Uint8
+func NewUint64Rule() FieldConversionRule {
+	return newNullInt64Rule(reflect.Uint64)
 }
 
-func NewUint16Rule() FieldConversionRule {
-	return &uint16Rule{}
+func NewFloat32Rule() FieldConversionRule {
+	return newNullFloat64Rule(reflect.Float32)
 }
 
-type uint16Rule struct{}
-
-func (r *uint16Rule) ValuePointer() interface{} {
-	return new(sql.NullInt32)
+func NewFloat64Rule() FieldConversionRule {
+	return newNullFloat64Rule(reflect.Float64)
 }
 
-func (r *uint16Rule) ConvertValue(v interface{}) (reflect.Value, error) {
-	if v == nil {
-		return reflect.ValueOf(uint16(0)), nil
-	}
-	if val, ok := v.(*sql.NullInt32); ok {
-		if val.Valid {
-			return reflect.ValueOf(uint16(val.Int32)), nil
-		}
-		return reflect.ValueOf(uint16(0)), nil
+func NewBoolRule() FieldConversionRule {
+	return &scalarRule{
+		name:    "bool",
+		accept:  kindOf(reflect.Bool),
+		pointer: func() interface{} { return new(sql.NullBool) },
+		value: func(v interface{}) (interface{}, bool, bool) {
+			val, ok := v.(*sql.NullBool)
+			if !ok {
+				return nil, false, false
+			}
+			return val.Bool, val.Valid, true
+		},
 	}
-	return reflect.ValueOf(nil), fmt.Errorf("Could not convert %T as int", v)
 }
 
-func (r *uint16Rule) CanConvert(ftype reflect.Type) bool {
-	return ftype.Kind() == reflect.Uint16
+func NewBinaryRule() FieldConversionRule {
+	return &scalarRule{
+		name: "binary",
+		accept: func(ftype reflect.Type) bool {
+			return ftype.Kind() == reflect.Slice && ftype.Elem().Kind() == reflect.Uint8
+		},
+		pointer: func() interface{} { return new([]byte) },
+		value: func(v interface{}) (interface{}, bool, bool) {
+			val, ok := v.(*[]byte)
+			if !ok {
+				return nil, false, false
+			}
+			return *val, *val != nil, true
+		},
+	}
 }
 
-func NewUint32Rule() FieldConversionRule {
-	return &uint32Rule{}
+// NewNullTypeRule handles fields declared with the sql.Null* types of database/sql.
+func NewNullTypeRule() FieldConversionRule {
+	return &nullTypeRule{}
 }
 
-type uint32Rule struct{}
-
-func (r *uint32Rule) ValuePointer() interface{} {
-	return new(sql.NullInt32)
-}
+type nullTypeRule struct{}
 
-func (r *uint32Rule) ConvertValue(v interface{}) (reflect.Value, error) {
-	if v == nil {
-		return reflect.ValueOf(uint32(0)), nil
-	}
-	if val, ok := v.(*sql.NullInt32); ok {
-		if val.Valid {
-			return reflect.ValueOf(uint32(val.Int32)), nil
+func (r *nullTypeRule) CanConvert(ftype reflect.Type) bool {
+	for _, t := range nulltypes {
+		if ftype == t {
+			return true
 		}
-		return reflect.ValueOf(uint32(0)), nil
 	}
-	return reflect.ValueOf(nil), fmt.Errorf("Could not convert %T as int", v)
+	return false
 }
 
-func (r *uint32Rule) CanConvert(ftype reflect.Type) bool {
-	return ftype.Kind() == reflect.Uint32
+func (r *nullTypeRule) Bind(field reflect.StructField) FieldConverter {
+	return &nullTypeConverter{ftype: field.Type}
 }
 
-func NewUint64Rule() FieldConversionRule {
-	return &uint64Rule{}
+type nullTypeConverter struct {
+	ftype reflect.Type
 }
 
-type uint64Rule struct{}
-
-func (r *uint64Rule) ValuePointer() interface{} {
-	return new(sql.NullInt64)
+func (c *nullTypeConverter) ValuePointer() interface{} {
+	return reflect.New(c.ftype).Interface()
 }
 
-func (r *uint64Rule) ConvertValue(v interface{}) (reflect.Value, error) {
+func (c *nullTypeConverter) ConvertValue(v interface{}) (reflect.Value, error) {
 	if v == nil {
-		return reflect.ValueOf(uint64(0)), nil
+		return reflect.Zero(c.ftype), nil
 	}
-	if val, ok := v.(*sql.NullInt64); ok {
-		if val.Valid {
-			return reflect.ValueOf(uint64(val.Int64)), nil
-		}
-		return reflect.ValueOf(uint64(0)), nil
+	val := reflect.ValueOf(v)
+	if val.Type() != reflect.PtrTo(c.ftype) {
+		return reflect.ValueOf(nil), fmt.Errorf("Could not convert %T as %v", v, c.ftype)
 	}
-	return reflect.ValueOf(nil), fmt.Errorf("Could not convert %T as int", v)
+	return val.Elem(), nil
 }
 
-func (r *uint64Rule) CanConvert(ftype reflect.Type) bool {
-	return ftype.Kind() == reflect.Uint64
+func (c *nullTypeConverter) DriverValue(v reflect.Value) (interface{}, error) {
+	return v.Interface().(driver.Valuer).Value()
 }
 
-func NewFloat32Rule() FieldConversionRule {
-	return &float32Rule{}
-}
-
-type float32Rule struct{}
+var (
+	scannertype = reflect.TypeOf((*sql.Scanner)(nil)).Elem()
+	valuertype  = reflect.TypeOf((*driver.Valuer)(nil)).Elem()
+)
 
-func (r *float32Rule) ValuePointer() interface{} {
-	return new(sql.NullFloat64)
+// NewScannerRule handles all field types implementing sql.Scanner, either directly
+// or via their pointer. Values are written via driver.Valuer if implemented.
+func NewScannerRule() FieldConversionRule {
+	return &scannerRule{}
 }
 
-func (r *float32Rule) ConvertValue(v interface{}) (reflect.Value, error) {
-	if v == nil {
-		return reflect.ValueOf(float32(0.0)), nil
-	}
-	if val, ok := v.(*sql.NullFloat64); ok {
-		if val.Valid {
-			return reflect.ValueOf(float32(val.Float64)), nil
-		}
-		return reflect.ValueOf(float32(0.0)), nil
+type scannerRule struct{}
+
+func (r *scannerRule) CanConvert(ftype reflect.Type) bool {
+	if ftype.Kind() == reflect.Ptr {
+		return ftype.Implements(scannertype)
 	}
-	return reflect.ValueOf(nil), fmt.Errorf("Could not convert %T as int", v)
+	return reflect.PtrTo(ftype).Implements(scannertype)
 }
 
-func (r *float32Rule) CanConvert(ftype reflect.Type) bool {
-	return ftype.Kind() == reflect.Float32
+func (r *scannerRule) Bind(field reflect.StructField) FieldConverter {
+	return &scannerConverter{ftype: field.Type}
 }
 
-func NewFloat64Rule() FieldConversionRule {
-	return &float64Rule{}
+type scannerConverter struct {
+	ftype reflect.Type
 }
 
-type float64Rule struct{}
-
-func (r *float64Rule) ValuePointer() interface{} {
-	return new(sql.NullFloat64)
+func (c *scannerConverter) ValuePointer() interface{} {
+	if c.ftype.Kind() == reflect.Ptr {
+		return &nullScanner{target: reflect.New(c.ftype.Elem())}
+	}
+	return reflect.New(c.ftype).Interface()
 }
 
-func (r *float64Rule) ConvertValue(v interface{}) (reflect.Value, error) {
+func (c *scannerConverter) ConvertValue(v interface{}) (reflect.Value, error) {
 	if v == nil {
-		return reflect.ValueOf(float64(0.0)), nil
+		return reflect.Zero(c.ftype), nil
 	}
-	if val, ok := v.(*sql.NullFloat64); ok {
-		if val.Valid {
-			return reflect.ValueOf(val.Float64), nil
+	if ns, ok := v.(*nullScanner); ok {
+		if !ns.valid {
+			return reflect.Zero(c.ftype), nil
 		}
-		return reflect.ValueOf(float64(0)), nil
+		ptr := reflect.New(c.ftype.Elem())
+		ptr.Elem().Set(ns.target.Elem())
+		return ptr, nil
 	}
-	return reflect.ValueOf(nil), fmt.Errorf("Could not convert %T as int", v)
-}
-
-func (r *float64Rule) CanConvert(ftype reflect.Type) bool {
-	return ftype.Kind() == reflect.Float64
-}
-
-func NewBoolRule() FieldConversionRule {
-	return &boolRule{}
-}
-
-type boolRule struct{}
-
-func (r *boolRule) ValuePointer() interface{} {
-	return new(sql.NullBool)
+	val := reflect.ValueOf(v)
+	if val.Type() != reflect.PtrTo(c.ftype) {
+		return reflect.ValueOf(nil), fmt.Errorf("Could not convert %T as %v", v, c.ftype)
+	}
+	return val.Elem(), nil
 }
 
-func (r *boolRule) ConvertValue(v interface{}) (reflect.Value, error) {
-	if v == nil {
-		return reflect.ValueOf(false), nil
+func (c *scannerConverter) DriverValue(v reflect.Value) (interface{}, error) {
+	if c.ftype.Kind() == reflect.Ptr && v.IsNil() {
+		return nil, nil
 	}
-	if val, ok := v.(*sql.NullBool); ok {
-		if val.Valid {
-			return reflect.ValueOf(val.Bool), nil
-		}
-		return reflect.ValueOf(false), nil
+	if v.Type().Implements(valuertype) {
+		return v.Interface().(driver.Valuer).Value()
 	}
-	return reflect.ValueOf(nil), fmt.Errorf("Could not convert %T as int", v)
-}
-
-func (r *boolRule) CanConvert(ftype reflect.Type) bool {
-	return ftype.Kind() == reflect.Bool
-}
-
-func NewBinaryRule() FieldConversionRule {
-	return &binaryRule{}
+	if v.CanAddr() && v.Addr().Type().Implements(valuertype) {
+		return v.Addr().Interface().(driver.Valuer).Value()
+	}
+	return v.Interface(), nil
 }
 
-type binaryRule struct{}
-
-func (r *binaryRule) ValuePointer() interface{} {
-	return new([]byte)
+// nullScanner scans into target unless the column is NULL.
+type nullScanner struct {
+	target reflect.Value
+	valid  bool
 }
 
-func (r *binaryRule) ConvertValue(v interface{}) (reflect.Value, error) {
-	if v == nil {
-		return reflect.ValueOf([]byte{}), nil
-	}
-	if val, ok := v.(*[]byte); ok {
-		return reflect.ValueOf(*val), nil
+func (ns *nullScanner) Scan(src interface{}) error {
+	ns.valid = src != nil
+	if !ns.valid {
+		return nil
 	}
-	return reflect.ValueOf(nil), fmt.Errorf("Could not convert %T as int", v)
-}
-
-func (r *binaryRule) CanConvert(ftype reflect.Type) bool {
-	return ftype.Kind() == reflect.Slice && ftype.Elem().Kind() == reflect.Uint8
+	return ns.target.Interface().(sql.Scanner).Scan(src)
 }
@@ -0,0 +1,148 @@
+package sql
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// roundTrip writes the given entity like an insert and reads the written values back
+// like a query, values pass the default conversion of database/sql drivers.
+func roundTrip(t *testing.T, entity interface{}) interface{} {
+	t.Helper()
+	builder := NewEntityBuilder(reflect.TypeOf(entity).Elem())
+	vals, err := builder.ValuesOf(entity, builder.Fields())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	scans := builder.ScanList()
+	for i, v := range vals {
+		dv, err := driver.DefaultParameterConverter.ConvertValue(v)
+		if err != nil {
+			t.Fatalf("Field %v: %v", builder.Fields()[i].Name(), err)
+		}
+		if scanner, ok := scans[i].(sql.Scanner); ok {
+			if err := scanner.Scan(dv); err != nil {
+				t.Fatalf("Field %v: %v", builder.Fields()[i].Name(), err)
+			}
+		} else if dv != nil {
+			reflect.ValueOf(scans[i]).Elem().Set(reflect.ValueOf(dv))
+		}
+	}
+
+	result := reflect.New(reflect.TypeOf(entity).Elem()).Interface()
+	if err := builder.Read(scans, result); err != nil {
+		t.Fatal(err)
+	}
+	return result
+}
+
+type testTimes struct {
+	Created time.Time
+	Updated *time.Time
+	Deleted *time.Time
+	Expires time.Time
+}
+
+func TestTimeRoundTrip(t *testing.T) {
+	created := time.Date(2020, 5, 17, 10, 30, 0, 0, time.UTC)
+	updated := created.Add(time.Hour)
+
+	tests := []*testTimes{
+		{Created: created, Updated: &updated, Expires: updated},
+		{Created: created},
+		{Updated: &updated, Deleted: &created},
+	}
+	for _, entity := range tests {
+		if got := roundTrip(t, entity); !reflect.DeepEqual(got, entity) {
+			t.Errorf("round trip = %+v, want %+v", got, entity)
+		}
+	}
+}
+
+type testPointers struct {
+	Int     *int
+	Int8    *int8
+	Int16   *int16
+	Int32   *int32
+	Int64   *int64
+	Uint    *uint
+	Uint8   *uint8
+	Uint16  *uint16
+	Uint32  *uint32
+	Uint64  *uint64
+	Float32 *float32
+	Float64 *float64
+	Bool    *bool
+	String  *string
+}
+
+func TestPointerRoundTrip(t *testing.T) {
+	i, i8, i16, i32, i64 := -1, int8(-8), int16(-16), int32(-32), int64(-64)
+	u, u8, u16, u32, u64 := uint(1), uint8(8), uint16(16), uint32(32), uint64(64)
+	f32, f64, b, s := float32(1.5), 2.5, true, "x"
+	zero, empty, no := 0, "", false
+
+	tests := []*testPointers{
+		{},
+		{&i, &i8, &i16, &i32, &i64, &u, &u8, &u16, &u32, &u64, &f32, &f64, &b, &s},
+		{Int: &zero, String: &empty, Bool: &no},
+	}
+	for _, entity := range tests {
+		if got := roundTrip(t, entity); !reflect.DeepEqual(got, entity) {
+			t.Errorf("round trip = %+v, want %+v", got, entity)
+		}
+	}
+}
+
+// testCode is stored with a prefix through sql.Scanner and driver.Valuer.
+type testCode string
+
+func (c testCode) Value() (driver.Value, error) {
+	return "C-" + string(c), nil
+}
+
+func (c *testCode) Scan(src interface{}) error {
+	s, _ := src.(string)
+	*c = testCode(strings.TrimPrefix(s, "C-"))
+	return nil
+}
+
+type testScanned struct {
+	Code    testCode
+	OptCode *testCode
+	Name    sql.NullString
+	Count   sql.NullInt64
+}
+
+func TestScannerRoundTrip(t *testing.T) {
+	code := testCode("b")
+	tests := []*testScanned{
+		{},
+		{Code: "a", OptCode: &code, Name: sql.NullString{String: "x", Valid: true}, Count: sql.NullInt64{Int64: 3, Valid: true}},
+	}
+	for _, entity := range tests {
+		if got := roundTrip(t, entity); !reflect.DeepEqual(got, entity) {
+			t.Errorf("round trip = %+v, want %+v", got, entity)
+		}
+	}
+
+	builder := NewEntityBuilder(reflect.TypeOf(testScanned{}))
+	vals, err := builder.ValuesOf(&testScanned{Code: "a"}, builder.Fields())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []interface{}{"C-a", nil, nil, nil}; !reflect.DeepEqual(vals, want) {
+		t.Errorf("values = %#v, want %#v", vals, want)
+	}
+
+	for _, fld := range builder.Fields("Code", "OptCode") {
+		if _, ok := fld.ConversionRule().(*nullTypeRule); !ok {
+			t.Errorf("field %v bound to %T, want the sql.Null* rule", fld.Name(), fld.ConversionRule())
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package sql
+
+import (
+	"database/sql"
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestSplitTag(t *testing.T) {
+	tests := []struct {
+		tag  string
+		want []string
+	}{
+		{"", []string{""}},
+		{"name", []string{"name"}},
+		{"name, pk ,omitempty", []string{"name", "pk", "omitempty"}},
+		{"price,type=DECIMAL(10,2),nullable", []string{"price", "type=DECIMAL(10,2)", "nullable"}},
+	}
+
+	for _, tt := range tests {
+		if got := splitTag(tt.tag); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("splitTag(%q) = %q, want %q", tt.tag, got, tt.want)
+		}
+	}
+}
+
+func TestParseFieldTag(t *testing.T) {
+	tests := []struct {
+		tag  string
+		want FieldTag
+	}{
+		{"-", FieldTag{Ignore: true}},
+		{"", FieldTag{}},
+		{"id,pk,generated", FieldTag{Column: "id", PrimaryKey: true, Generated: true}},
+		{",autoincrement", FieldTag{Generated: true}},
+		{"name,omitempty,nullable,readonly", FieldTag{Column: "name", OmitEmpty: true, Nullable: true, ReadOnly: true}},
+		{"price,type=DECIMAL(10,2)", FieldTag{Column: "price", Type: "DECIMAL(10,2)"}},
+		{"data,JSON", FieldTag{Column: "data", JSON: true}},
+		{",version", FieldTag{Version: true}},
+		{",deleted", FieldTag{Deleted: true}},
+		{",audit=createdAt", FieldTag{Audit: "createdAt"}},
+		{",inline,prefix=home_", FieldTag{Inline: true, Prefix: "home_"}},
+		{"name,unknown", FieldTag{Column: "name"}},
+	}
+
+	for _, tt := range tests {
+		if got := ParseFieldTag(tt.tag); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ParseFieldTag(%q) = %+v, want %+v", tt.tag, got, tt.want)
+		}
+	}
+}
+
+type testTagged struct {
+	ID      int64             `sql:"user_id,pk"`
+	Name    string            `sql:"user_name"`
+	Price   float64           `sql:"price,type=DECIMAL(10,2)"`
+	Data    map[string]string `sql:"data,type=json"`
+	Skipped string            `sql:"-"`
+}
+
+func TestTaggedColumns(t *testing.T) {
+	h, err := NewHandler(&HandlerSettings{Database: &sql.DB{}, Dialect: Postgres, Type: reflect.TypeOf(testTagged{}), Table: "users"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sh := h.(*sqlHandler)
+
+	if sh.indexCol != "user_id" {
+		t.Errorf("index column = %v, want the primary key user_id", sh.indexCol)
+	}
+	if _, ok := sh.builder.Field("Skipped"); ok {
+		t.Error("expected ignored field Skipped")
+	}
+
+	want := "INSERT INTO users (user_id, user_name, price, data) VALUES (?, ?, CAST(? AS DECIMAL(10,2)), ?)"
+	if got := sh.buildInsert(sh.builder.Fields()); got != want {
+		t.Errorf("buildInsert = %q, want %q", got, want)
+	}
+
+	data, _ := sh.builder.Field("Data")
+	dv, err := data.Converter().DriverValue(reflect.ValueOf(map[string]string{"a": "b"}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s := fmt.Sprintf("%s", dv); s != `{"a":"b"}` {
+		t.Errorf("json value = %v, want {\"a\":\"b\"}", s)
+	}
+}
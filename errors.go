@@ -0,0 +1,176 @@
+package sql
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var (
+	// ErrNotFound is returned if the requested entity does not exist.
+	ErrNotFound = errors.New("Entity not found")
+	// ErrConflict is returned on unique or primary key violations.
+	ErrConflict = errors.New("Conflict with existing entity")
+	// ErrConstraint is returned on foreign key, not null and check constraint violations.
+	ErrConstraint = errors.New("Constraint violation")
+	// ErrTransient is returned on deadlocks, serialization failures and lock timeouts,
+	// the operation may succeed if it is repeated. Connection errors are transient as well.
+	ErrTransient = errors.New("Transient failure")
+	// ErrTimeout is returned if an operation exceeded its timeout.
+	ErrTimeout = errors.New("Timeout exceeded")
+	// ErrConnection is returned if the connection to the database is lost.
+	ErrConnection = errors.New("Connection failure")
+)
+
+// Error is the error returned by sql handlers for classified failures. Use errors.Is
+// with the Err* kinds and errors.As to get the code of the database.
+type Error struct {
+	Kind error
+	// Code is the SQLSTATE or driver specific error code if known.
+	Code string
+	Err  error
+}
+
+func (e *Error) Error() string {
+	if e.Err == nil {
+		return e.Kind.Error()
+	}
+	if e.Code == "" {
+		return fmt.Sprintf("%v: %v", e.Kind, e.Err)
+	}
+	return fmt.Sprintf("%v (%v): %v", e.Kind, e.Code, e.Err)
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+func (e *Error) Is(target error) bool {
+	if target == e.Kind {
+		return true
+	}
+	return target == ErrTransient && e.Kind == ErrConnection
+}
+
+func notFound(id interface{}) error {
+	return &Error{
+		Kind: ErrNotFound,
+		Err:  fmt.Errorf("Could not find entity with id %v", id),
+	}
+}
+
+// ErrorTranslator is implemented by dialects that know the error codes of their drivers.
+// TranslateError returns nil if the error is unknown.
+type ErrorTranslator interface {
+	TranslateError(error) *Error
+}
+
+// TranslateError classifies the given error with the Err* kinds. Errors that
+// could not be classified are returned unchanged.
+func TranslateError(dialect Dialect, err error) error {
+	if err == nil {
+		return nil
+	}
+	var e *Error
+	if errors.As(err, &e) {
+		return err
+	}
+
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		return &Error{Kind: ErrTimeout, Err: err}
+	case errors.Is(err, driver.ErrBadConn):
+		return &Error{Kind: ErrConnection, Err: err}
+	}
+
+	if translator, ok := dialect.(ErrorTranslator); ok {
+		if result := translator.TranslateError(err); result != nil {
+			return result
+		}
+	}
+
+	var state interface{ SQLState() string }
+	if errors.As(err, &state) {
+		if kind := sqlStateKind(state.SQLState()); kind != nil {
+			return &Error{Kind: kind, Code: state.SQLState(), Err: err}
+		}
+	}
+	return err
+}
+
+// sqlStateKind classifies the standard SQLSTATE codes.
+func sqlStateKind(state string) error {
+	switch {
+	case state == "23505":
+		return ErrConflict
+	case strings.HasPrefix(state, "23"):
+		return ErrConstraint
+	case strings.HasPrefix(state, "40"), state == "57033", state == "55P03":
+		return ErrTransient
+	case state == "57014":
+		return ErrTimeout
+	case strings.HasPrefix(state, "08"), state == "57P01", state == "57P02", state == "57P03":
+		return ErrConnection
+	}
+	return nil
+}
+
+var db2StatePattern = regexp.MustCompile(`SQLSTATE=([0-9A-Z]{5})`)
+
+func (d *db2Dialect) TranslateError(err error) *Error {
+	match := db2StatePattern.FindStringSubmatch(err.Error())
+	if match == nil {
+		return nil
+	}
+	if kind := sqlStateKind(match[1]); kind != nil {
+		return &Error{Kind: kind, Code: match[1], Err: err}
+	}
+	return nil
+}
+
+var mysqlCodePattern = regexp.MustCompile(`^Error (\d+)`)
+
+func (d *mysqlDialect) TranslateError(err error) *Error {
+	match := mysqlCodePattern.FindStringSubmatch(err.Error())
+	if match == nil {
+		return nil
+	}
+	code, _ := strconv.Atoi(match[1])
+
+	var kind error
+	switch code {
+	case 1062, 1586:
+		kind = ErrConflict
+	case 1048, 1216, 1217, 1451, 1452, 3819:
+		kind = ErrConstraint
+	case 1205, 1213:
+		kind = ErrTransient
+	case 3024:
+		kind = ErrTimeout
+	case 1053, 2006, 2013:
+		kind = ErrConnection
+	default:
+		return nil
+	}
+	return &Error{Kind: kind, Code: match[1], Err: err}
+}
+
+func (d *sqliteDialect) TranslateError(err error) *Error {
+	msg := err.Error()
+	var kind error
+	switch {
+	case strings.Contains(msg, "UNIQUE constraint failed"), strings.Contains(msg, "PRIMARY KEY constraint failed"):
+		kind = ErrConflict
+	case strings.Contains(msg, "constraint failed"):
+		kind = ErrConstraint
+	case strings.Contains(msg, "database is locked"), strings.Contains(msg, "database table is locked"):
+		kind = ErrTransient
+	default:
+		return nil
+	}
+	return &Error{Kind: kind, Err: err}
+}
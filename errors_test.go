@@ -0,0 +1,72 @@
+package sql
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+// testStateError is a driver error exposing its SQLSTATE like pq and pgx errors.
+type testStateError string
+
+func (e testStateError) Error() string    { return "driver error " + string(e) }
+func (e testStateError) SQLState() string { return string(e) }
+
+func TestTranslateError(t *testing.T) {
+	unknown := errors.New("syntax error")
+	tests := []struct {
+		name    string
+		dialect Dialect
+		err     error
+		kind    error
+		code    string
+	}{
+		{"deadline", Postgres, fmt.Errorf("query: %w", context.DeadlineExceeded), ErrTimeout, ""},
+		{"bad connection", Postgres, driver.ErrBadConn, ErrConnection, ""},
+		{"unique state", Postgres, testStateError("23505"), ErrConflict, "23505"},
+		{"foreign key state", Postgres, testStateError("23503"), ErrConstraint, "23503"},
+		{"serialization state", Postgres, testStateError("40001"), ErrTransient, "40001"},
+		{"canceled statement", Postgres, testStateError("57014"), ErrTimeout, "57014"},
+		{"admin shutdown", Postgres, testStateError("57P01"), ErrConnection, "57P01"},
+		{"db2 message", DB2, errors.New("SQL0803N One or more values ... SQLSTATE=23505"), ErrConflict, "23505"},
+		{"db2 lock timeout", DB2, errors.New("SQL0911N The current transaction has been rolled back. SQLSTATE=40001"), ErrTransient, "40001"},
+		{"mysql duplicate", MySQL, errors.New("Error 1062: Duplicate entry '1' for key 'PRIMARY'"), ErrConflict, "1062"},
+		{"mysql deadlock", MySQL, errors.New("Error 1213: Deadlock found"), ErrTransient, "1213"},
+		{"mysql not null", MySQL, errors.New("Error 1048: Column 'name' cannot be null"), ErrConstraint, "1048"},
+		{"sqlite unique", SQLite, errors.New("UNIQUE constraint failed: users.id"), ErrConflict, ""},
+		{"sqlite not null", SQLite, errors.New("NOT NULL constraint failed: users.name"), ErrConstraint, ""},
+		{"sqlite locked", SQLite, errors.New("database is locked"), ErrTransient, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := TranslateError(tt.dialect, tt.err)
+			if !errors.Is(err, tt.kind) {
+				t.Fatalf("TranslateError = %v, want %v", err, tt.kind)
+			}
+			if !errors.Is(err, tt.err) {
+				t.Errorf("TranslateError = %v, want it to wrap %v", err, tt.err)
+			}
+			var e *Error
+			if !errors.As(err, &e) || e.Code != tt.code {
+				t.Errorf("code = %v, want %v", e, tt.code)
+			}
+		})
+	}
+
+	if err := TranslateError(Postgres, unknown); err != unknown {
+		t.Errorf("TranslateError = %v, want the unknown error unchanged", err)
+	}
+	if err := TranslateError(Postgres, nil); err != nil {
+		t.Errorf("TranslateError(nil) = %v", err)
+	}
+	translated := TranslateError(Postgres, notFound(1))
+	if !errors.Is(translated, ErrNotFound) || TranslateError(Postgres, translated) != translated {
+		t.Errorf("TranslateError = %v, want classified errors unchanged", translated)
+	}
+	if !errors.Is(&Error{Kind: ErrConnection}, ErrTransient) {
+		t.Error("expected connection failures to be transient")
+	}
+}
@@ -2,104 +2,135 @@ package sql
 
 import (
 	"fmt"
+	"regexp"
 	"strings"
-	"time"
 
 	"github.com/ioswarm/golik"
 )
 
-func NewFilter(cond golik.Condition) (string, error) {
-	result, err := interpretCondition(cond)
+var identifierPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*(\.[A-Za-z_][A-Za-z0-9_]*)*$`)
+
+// likeEscape is the escape character used for LIKE patterns built from filter values.
+const likeEscape = "!"
+
+// NewFilter interprets the given condition as sql where-clause. All values are
+// returned as ordered arguments and referenced by '?' placeholders in the clause.
+func NewFilter(cond golik.Condition) (string, []interface{}, error) {
+	return newFilterBuilder(nil).build(cond)
+}
+
+type filterBuilder struct {
+	args   []interface{}
+	column func(string) string
+}
+
+func newFilterBuilder(column func(string) string) *filterBuilder {
+	if column == nil {
+		column = func(attr string) string { return attr }
+	}
+	return &filterBuilder{
+		args:   make([]interface{}, 0),
+		column: column,
+	}
+}
+
+func (fb *filterBuilder) build(cond golik.Condition) (string, []interface{}, error) {
+	result, err := fb.interpretCondition(cond)
 	if err != nil {
-		return "", err
+		return "", nil, err
 	}
 	if result != "" {
-		return "WHERE " + strings.TrimSpace(result), nil
+		return "WHERE " + strings.TrimSpace(result), fb.args, nil
 	}
-	return "", nil
+	return "", fb.args, nil
 }
 
-func interpretCondition(condition golik.Condition) (string, error) {
+func (fb *filterBuilder) bind(value interface{}) string {
+	fb.args = append(fb.args, value)
+	return "?"
+}
+
+func (fb *filterBuilder) interpretCondition(condition golik.Condition) (string, error) {
 	switch condition.(type) {
 	case golik.Operand:
 		operand := condition.(golik.Operand)
-		return interpretOperand(operand)
+		return fb.interpretOperand(operand)
 	case golik.Logic:
 		logic := condition.(golik.Logic)
-		return interpretLogical(logic)
+		return fb.interpretLogical(logic)
 	case golik.LogicNot:
 		not := condition.(golik.LogicNot)
-		return interpretNot(not)
+		return fb.interpretNot(not)
 	case golik.Grouping:
 		grp := condition.(golik.Grouping)
-		return interpretGrouping(grp)
+		return fb.interpretGrouping(grp)
 	default:
 		return "", nil
 	}
 }
 
-func toSqlValue(value interface{}) interface{} {
-	switch value.(type) {
-	case string:
-		return fmt.Sprintf("'%v'", value)
-	case time.Time:
-		t := value.(time.Time)
-		return fmt.Sprintf("'%v'", t.Format("2006-01-02 15:04:05.000"))
-	case *time.Time:
-		t := value.(*time.Time)
-		return fmt.Sprintf("'%v'", t.Format("2006-01-02 15:04:05.000"))
-	default:
-		return value
-	}
+func escapeLike(value interface{}) string {
+	s := fmt.Sprint(value)
+	s = strings.ReplaceAll(s, likeEscape, likeEscape+likeEscape)
+	s = strings.ReplaceAll(s, "%", likeEscape+"%")
+	return strings.ReplaceAll(s, "_", likeEscape+"_")
+}
+
+func (fb *filterBuilder) like(attr string, pattern string) string {
+	return fmt.Sprintf("%v LIKE %v ESCAPE '%v'", attr, fb.bind(pattern), likeEscape)
 }
 
-func interpretOperand(op golik.Operand) (string, error) {
-	value := toSqlValue(op.Value())
+func (fb *filterBuilder) interpretOperand(op golik.Operand) (string, error) {
+	attr := op.Attribute()
+	if !identifierPattern.MatchString(attr) {
+		return "", fmt.Errorf("Invalid attribute %q", attr)
+	}
+	attr = fb.column(attr)
 
 	switch op.Operator() {
 	case golik.EQ:
-		return fmt.Sprintln(op.Attribute(), "=", value), nil
+		return fmt.Sprintln(attr, "=", fb.bind(op.Value())), nil
 	case golik.NE:
-		return fmt.Sprintln(op.Attribute(), "!=", value), nil
+		return fmt.Sprintln(attr, "!=", fb.bind(op.Value())), nil
 	case golik.CO:
-		return fmt.Sprintf("%v LIKE '%%%s%%'", op.Attribute(), op.Value()), nil
+		return fb.like(attr, "%"+escapeLike(op.Value())+"%"), nil
 	case golik.SW:
-		return fmt.Sprintf("%v LIKE '%s%%'", op.Attribute(), op.Value()), nil
+		return fb.like(attr, escapeLike(op.Value())+"%"), nil
 	case golik.EW:
-		return fmt.Sprintf("%v LIKE '%%%s'", op.Attribute(), op.Value()), nil
+		return fb.like(attr, "%"+escapeLike(op.Value())), nil
 	case golik.PR:
-		return fmt.Sprintln(op.Attribute(), "IS NOT NULL"), nil
+		return fmt.Sprintln(attr, "IS NOT NULL"), nil
 	case golik.GT:
-		return fmt.Sprintln(op.Attribute(), ">", value), nil
+		return fmt.Sprintln(attr, ">", fb.bind(op.Value())), nil
 	case golik.GE:
-		return fmt.Sprintln(op.Attribute(), ">=", value), nil
+		return fmt.Sprintln(attr, ">=", fb.bind(op.Value())), nil
 	case golik.LT:
-		return fmt.Sprintln(op.Attribute(), "<", value), nil
+		return fmt.Sprintln(attr, "<", fb.bind(op.Value())), nil
 	case golik.LE:
-		return fmt.Sprintln(op.Attribute(), "<=", value), nil
+		return fmt.Sprintln(attr, "<=", fb.bind(op.Value())), nil
 	default:
 		return "", fmt.Errorf("Unsupported operator %v", op.Operator())
 	}
 }
 
-func interpretLogical(logic golik.Logic) (string, error) {
+func (fb *filterBuilder) interpretLogical(logic golik.Logic) (string, error) {
 	switch logic.Logical() {
 	case golik.AND:
-		l, err := interpretCondition(logic.Left())
+		l, err := fb.interpretCondition(logic.Left())
 		if err != nil {
 			return "", err
 		}
-		r, err := interpretCondition(logic.Right())
+		r, err := fb.interpretCondition(logic.Right())
 		if err != nil {
 			return "", err
 		}
 		return fmt.Sprintln(l, "AND", r), nil
 	case golik.OR:
-		l, err := interpretCondition(logic.Left())
+		l, err := fb.interpretCondition(logic.Left())
 		if err != nil {
 			return "", err
 		}
-		r, err := interpretCondition(logic.Right())
+		r, err := fb.interpretCondition(logic.Right())
 		if err != nil {
 			return "", err
 		}
@@ -109,16 +140,16 @@ func interpretLogical(logic golik.Logic) (string, error) {
 	}
 }
 
-func interpretNot(not golik.LogicNot) (string, error) {
-	inner, err := interpretCondition(not.InnerNot())
+func (fb *filterBuilder) interpretNot(not golik.LogicNot) (string, error) {
+	inner, err := fb.interpretCondition(not.InnerNot())
 	if err != nil {
 		return "", err
 	}
 	return fmt.Sprintln("not (", inner, ")"), nil
 }
 
-func interpretGrouping(grp golik.Grouping) (string, error) {
-	inner, err := interpretCondition(grp.InnerGroup())
+func (fb *filterBuilder) interpretGrouping(grp golik.Grouping) (string, error) {
+	inner, err := fb.interpretCondition(grp.InnerGroup())
 	if err != nil {
 		return "", err
 	}
@@ -0,0 +1,129 @@
+package sql
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/ioswarm/golik"
+)
+
+type testOperand struct {
+	attribute string
+	operator  golik.Operator
+	value     interface{}
+}
+
+func (o testOperand) Attribute() string        { return o.attribute }
+func (o testOperand) Operator() golik.Operator { return o.operator }
+func (o testOperand) Value() interface{}       { return o.value }
+
+type testLogic struct {
+	logical     golik.Logical
+	left, right golik.Condition
+}
+
+func (l testLogic) Logical() golik.Logical { return l.logical }
+func (l testLogic) Left() golik.Condition  { return l.left }
+func (l testLogic) Right() golik.Condition { return l.right }
+
+type testNot struct{ inner golik.Condition }
+
+func (n testNot) InnerNot() golik.Condition { return n.inner }
+
+type testGroup struct{ inner golik.Condition }
+
+func (g testGroup) InnerGroup() golik.Condition { return g.inner }
+
+func TestFilterBuilder(t *testing.T) {
+	tests := []struct {
+		name   string
+		cond   golik.Condition
+		column func(string) string
+		where  string
+		args   []interface{}
+		err    bool
+	}{
+		{
+			name:  "empty",
+			cond:  nil,
+			where: "",
+			args:  []interface{}{},
+		},
+		{
+			name:  "equal",
+			cond:  testOperand{"name", golik.EQ, "x"},
+			where: "WHERE name = ?",
+			args:  []interface{}{"x"},
+		},
+		{
+			name:  "present",
+			cond:  testOperand{"name", golik.PR, nil},
+			where: "WHERE name IS NOT NULL",
+			args:  []interface{}{},
+		},
+		{
+			name:  "contains escapes like wildcards",
+			cond:  testOperand{"name", golik.CO, "50%_off!"},
+			where: "WHERE name LIKE ? ESCAPE '!'",
+			args:  []interface{}{"%50!%!_off!!%"},
+		},
+		{
+			name:  "starts with",
+			cond:  testOperand{"name", golik.SW, "a_"},
+			where: "WHERE name LIKE ? ESCAPE '!'",
+			args:  []interface{}{"a!_%"},
+		},
+		{
+			name:  "ends with",
+			cond:  testOperand{"name", golik.EW, "%z"},
+			where: "WHERE name LIKE ? ESCAPE '!'",
+			args:  []interface{}{"%!%z"},
+		},
+		{
+			name: "arguments in order of placeholders",
+			cond: testLogic{golik.OR,
+				testOperand{"a", golik.GT, 1},
+				testNot{testGroup{testLogic{golik.AND,
+					testOperand{"b", golik.LE, 2},
+					testOperand{"c", golik.NE, 3},
+				}}},
+			},
+			where: "WHERE a > ? OR not ( ( b <= ? AND c != ? ) )",
+			args:  []interface{}{1, 2, 3},
+		},
+		{
+			name:   "mapped columns",
+			cond:   testOperand{"firstName", golik.EQ, "x"},
+			column: func(attr string) string { return `"FIRST_NAME"` },
+			where:  `WHERE "FIRST_NAME" = ?`,
+			args:   []interface{}{"x"},
+		},
+		{
+			name: "invalid attribute",
+			cond: testOperand{"name = name OR 1", golik.EQ, 1},
+			err:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			where, args, err := newFilterBuilder(tt.column).build(tt.cond)
+			if tt.err {
+				if err == nil {
+					t.Fatalf("expected error, got %q", where)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := strings.Join(strings.Fields(where), " "); got != tt.where {
+				t.Errorf("where = %q, want %q", got, tt.where)
+			}
+			if !reflect.DeepEqual(args, tt.args) {
+				t.Errorf("args = %v, want %v", args, tt.args)
+			}
+		})
+	}
+}
@@ -0,0 +1,151 @@
+package sql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/ioswarm/golik"
+)
+
+// InsertReturner is implemented by dialects that return the stored row of an insert statement.
+type InsertReturner interface {
+	// InsertReturning changes the given insert of a single row to return the given columns, if supported.
+	InsertReturning(insert string, columns []string) string
+}
+
+func (d *db2Dialect) InsertReturning(insert string, columns []string) string {
+	return fmt.Sprintf("SELECT %v FROM FINAL TABLE (%v)", strings.Join(columns, ", "), insert)
+}
+
+func (d *postgresDialect) InsertReturning(insert string, columns []string) string {
+	return fmt.Sprintf("%v RETURNING %v", insert, strings.Join(columns, ", "))
+}
+
+func (d *sqliteDialect) InsertReturning(insert string, columns []string) string {
+	return ""
+}
+
+func (d *sqlServerDialect) InsertReturning(insert string, columns []string) string {
+	output := make([]string, len(columns))
+	for i, c := range columns {
+		output[i] = "INSERTED." + c
+	}
+	i := strings.LastIndex(insert, " VALUES (")
+	return fmt.Sprintf("%v OUTPUT %v%v", insert[:i], strings.Join(output, ", "), insert[i:])
+}
+
+// generatedFields returns the fields whose values are generated by the database, tagged
+// with `sql:",generated"` by default.
+func generatedFields(builder EntityBuilder, names []string) ([]Field, error) {
+	return lookupFields(builder, "generated", names, func(tag FieldTag) bool { return tag.Generated })
+}
+
+// autoIncrement returns the generated integer field populated by LastInsertId,
+// the index field if it is generated.
+func (h *sqlHandler) autoIncrement() Field {
+	var result Field
+	for _, fld := range h.generated {
+		switch fld.Field().Type.Kind() {
+		case reflect.Int, reflect.Int32, reflect.Int64, reflect.Uint, reflect.Uint32, reflect.Uint64:
+			if matchesField(fld, h.indexField) {
+				return fld
+			}
+			if result == nil {
+				result = fld
+			}
+		}
+	}
+	return result
+}
+
+func (h *sqlHandler) generatedNames() []string {
+	result := make([]string, len(h.generated))
+	for i, fld := range h.generated {
+		result[i] = fld.Name()
+	}
+	return result
+}
+
+// insertable returns the fields written by inserts of the given entity except the given fields.
+func (h *sqlHandler) insertable(entity interface{}, expects ...string) []Field {
+	return h.builder.Writable(entity, append(h.generatedNames(), expects...)...)
+}
+
+// readInto scans the single row of the given rows into the entity and tells whether there was a row.
+func (h *sqlHandler) readInto(rows *sql.Rows, entity interface{}) (bool, error) {
+	defer rows.Close()
+
+	vals := h.builder.ScanList()
+	if !rows.Next() {
+		return false, rows.Err()
+	}
+	if err := rows.Scan(vals...); err != nil {
+		return false, err
+	}
+	if err := h.builder.Read(vals, entity); err != nil {
+		return false, err
+	}
+	return true, rows.Err()
+}
+
+// insert executes the given insert statement and populates the entity with the stored row, either
+// returned by the statement or read after it. Generated keys are read by LastInsertId if the
+// dialect is not able to return the row.
+func (h *sqlHandler) insert(c context.Context, ctx golik.CloveContext, tx *sql.Tx, qry string, vals []interface{}, entity interface{}) error {
+	returning := ""
+	if returner, ok := h.dialect.(InsertReturner); ok {
+		returning = returner.InsertReturning(qry, h.quoteAll(h.builder.SqlNames()))
+	}
+	if returning != "" {
+		stmt, err := h.prepare(c, ctx, tx, returning)
+		if err != nil {
+			return err
+		}
+		defer stmt.Close()
+
+		rows, err := stmt.QueryContext(c, vals...)
+		if err != nil {
+			return err
+		}
+		_, err = h.readInto(rows, entity)
+		return err
+	}
+
+	stmt, err := h.prepare(c, ctx, tx, qry)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	res, err := stmt.ExecContext(c, vals...)
+	if err != nil {
+		return err
+	}
+
+	if fld := h.autoIncrement(); fld != nil {
+		if id, err := res.LastInsertId(); err == nil {
+			v, _ := fieldByIndex(reflect.ValueOf(entity).Elem(), fld.Field().Index, true)
+			switch v.Kind() {
+			case reflect.Uint, reflect.Uint32, reflect.Uint64:
+				v.SetUint(uint64(id))
+			default:
+				v.SetInt(id)
+			}
+		}
+	}
+
+	id, err := h.builder.ValueOf(entity, h.indexField)
+	if err != nil {
+		return err
+	}
+	if id == nil || reflect.ValueOf(id).IsZero() {
+		// the key is unknown, e.g. generated by a sequence without support of the dialect
+		return nil
+	}
+
+	_, err = h.reread(c, ctx, tx, id, entity)
+	return err
+}
@@ -0,0 +1,60 @@
+package sql
+
+import (
+	"database/sql"
+	"reflect"
+	"testing"
+)
+
+type testGenerated struct {
+	ID      int64 `sql:",generated"`
+	Name    string
+	Created string `sql:",generated"`
+}
+
+func TestGeneratedFields(t *testing.T) {
+	h, err := NewHandler(&HandlerSettings{Database: &sql.DB{}, Dialect: Postgres, Type: reflect.TypeOf(testGenerated{}), Table: "items"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sh := h.(*sqlHandler)
+
+	if got := sh.generatedNames(); !reflect.DeepEqual(got, []string{"ID", "Created"}) {
+		t.Errorf("generated = %v, want [ID Created]", got)
+	}
+	if fld := sh.autoIncrement(); fld == nil || fld.Name() != "ID" {
+		t.Errorf("auto increment = %v, want ID", fld)
+	}
+	if want := "INSERT INTO items (NAME) VALUES (?)"; sh.buildInsert(sh.insertable(&testGenerated{Name: "x"})) != want {
+		t.Errorf("insert of generated fields, want %q", want)
+	}
+
+	if _, err := NewHandler(&HandlerSettings{Database: &sql.DB{}, Dialect: Postgres, Type: reflect.TypeOf(testItem{}), GeneratedFields: []string{"Unknown"}}); err == nil {
+		t.Error("expected error for unknown generated field")
+	}
+}
+
+func TestInsertReturning(t *testing.T) {
+	insert := "INSERT INTO items (NAME) VALUES (?)"
+	columns := []string{"ID", "NAME"}
+	tests := []struct {
+		dialect Dialect
+		want    string
+	}{
+		{DB2, "SELECT ID, NAME FROM FINAL TABLE (INSERT INTO items (NAME) VALUES (?))"},
+		{Postgres, "INSERT INTO items (NAME) VALUES (?) RETURNING ID, NAME"},
+		{SQLite, ""},
+		{SQLServer, "INSERT INTO items (NAME) OUTPUT INSERTED.ID, INSERTED.NAME VALUES (?)"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.dialect.(InsertReturner).InsertReturning(insert, columns); got != tt.want {
+			t.Errorf("%v InsertReturning = %q, want %q", tt.dialect.Name(), got, tt.want)
+		}
+	}
+	for _, d := range []Dialect{MySQL, Oracle} {
+		if _, ok := d.(InsertReturner); ok {
+			t.Errorf("%v does not return inserted rows", d.Name())
+		}
+	}
+}
@@ -1,7 +1,9 @@
 package sql
 
 import (
+	"context"
 	"fmt"
+	"time"
 
 	"database/sql"
 	"reflect"
@@ -10,107 +12,402 @@ import (
 	"github.com/ioswarm/golik"
 )
 
-var baseFilterQuery = `
-select %s from (
-  select * from (
-	select 
-	  row_number() over (order by a.%s) as line_num, 
-	  a.* 
-	from (
-      %s
-	) a
-  ) x
-  where x.line_num between %d and %d
-) y
-`
-
-func defaultHandlerCreation(db *sql.DB, itype reflect.Type, indexField string, schema string, table string, behavior interface{}) golik.HandlerCreation {
+type HandlerSettings struct {
+	Database         *sql.DB
+	Dialect          Dialect
+	Type             reflect.Type
+	IndexField       string
+	Schema           string
+	Table            string
+	QuoteIdentifiers bool
+	Naming           NamingStrategy
+	Sort             []Sort
+	CountMode        CountMode
+	CountLimit       int
+	QueryTimeout     time.Duration
+	WriteTimeout     time.Duration
+	Retry            *RetryPolicy
+	VersionField     string
+	SoftDeleteField  string
+	Audit            Audit
+	UpsertKey        []string
+	BatchSize        int
+	GeneratedFields  []string
+	Clock            Clock
+	Actor            ActorFunc
+	Behavior         interface{}
+}
+
+// defaultHandlerCreation registers the created handlers for units of work of the service.
+func defaultHandlerCreation(settings *HandlerSettings, registry *handlerRegistry) golik.HandlerCreation {
 	return func(ctx golik.CloveContext) (golik.Handler, error) {
-		return NewSqlHandler(db, itype, indexField, schema, table, behavior)
+		h, err := NewHandler(settings)
+		if err != nil {
+			return nil, err
+		}
+		registry.register(h)
+		return h, nil
 	}
 }
 
 func NewSqlHandler(db *sql.DB, itype reflect.Type, indexField string, schema string, table string, behavior interface{}) (golik.Handler, error) {
-	if db == nil {
+	return NewHandler(&HandlerSettings{
+		Database:   db,
+		Dialect:    DB2,
+		Type:       itype,
+		IndexField: indexField,
+		Schema:     schema,
+		Table:      table,
+		Behavior:   behavior,
+	})
+}
+
+func NewHandler(settings *HandlerSettings) (golik.Handler, error) {
+	if settings.Database == nil {
 		return nil, golik.Errorln("Database connection is nil")
 	}
-	if itype.Kind() != reflect.Struct {
+	if settings.Type.Kind() != reflect.Struct {
 		return nil, golik.Errorln("Given type must be a struct")
 	}
 
-	fld := indexField
+	builder := NewEntityBuilder(settings.Type)
+	if settings.Naming != nil {
+		builder.WithNaming(settings.Naming)
+	}
+
+	fld := settings.IndexField
 	if fld == "" {
-		if itype.NumField() == 0 {
+		fields := builder.Fields()
+		if len(fields) == 0 {
 			return nil, golik.Errorf("Given type has no fields")
 		}
-		ftype := itype.Field(0)
-		fld = golik.CamelCase(ftype.Name)
+		if pk, ok := builder.PrimaryKey(); ok {
+			fld = golik.CamelCase(pk.Name())
+		} else {
+			// the first mapped field, embedded structs are flattened into their fields
+			fld = golik.CamelCase(fields[0].Name())
+		}
+	}
+
+	index, ok := builder.Field(fld)
+	if !ok {
+		return nil, golik.Errorf("Unknown index field %v", fld)
+	}
+	idxcol := index.SQLName()
+
+	version, err := versionField(builder, settings.VersionField)
+	if err != nil {
+		return nil, err
+	}
+
+	deleted, err := softDeleteField(builder, settings.SoftDeleteField)
+	if err != nil {
+		return nil, err
+	}
+
+	auditing, err := auditFieldsOf(builder, settings.Audit)
+	if err != nil {
+		return nil, err
+	}
+
+	generated, err := generatedFields(builder, settings.GeneratedFields)
+	if err != nil {
+		return nil, err
+	}
+
+	upsertKey, err := upsertKeyFields(builder, settings.UpsertKey, fld, generated)
+	if err != nil {
+		return nil, err
+	}
+
+	actor := settings.Actor
+	if actor == nil {
+		actor = ContextActor
+	}
+
+	dialect := settings.Dialect
+	if dialect == nil {
+		dialect = DB2
 	}
 
 	return &sqlHandler{
-		database:   db,
-		itype:      itype,
-		indexField: fld,
-		behavior:   behavior,
-		schema:     schema,
-		table:      table,
-		builder:    NewEntityBuilder(itype),
+		database:     settings.Database,
+		dialect:      dialect,
+		quoting:      settings.QuoteIdentifiers,
+		itype:        settings.Type,
+		indexField:   fld,
+		indexCol:     idxcol,
+		version:      version,
+		deleted:      deleted,
+		auditing:     auditing,
+		upsertKey:    upsertKey,
+		chunkSize:    settings.BatchSize,
+		generated:    generated,
+		clock:        settings.Clock,
+		actor:        actor,
+		sort:         settings.Sort,
+		countMode:    settings.CountMode,
+		countLimit:   settings.CountLimit,
+		queryTimeout: settings.QueryTimeout,
+		writeTimeout: settings.WriteTimeout,
+		retryPolicy:  settings.Retry,
+		behavior:     settings.Behavior,
+		schema:       settings.Schema,
+		table:        settings.Table,
+		builder:      builder,
 	}, nil
 }
 
 type sqlHandler struct {
-	database   *sql.DB
-	itype      reflect.Type
-	indexField string
-	schema     string
-	table      string
-	builder    EntityBuilder
-	behavior   interface{}
-}
-
-func (h *sqlHandler) count(ctx golik.CloveContext, where string) int {
-	qry := "SELECT count(*) as cnt from " + h.tablePath() + " " + where
-	rows, err := h.database.Query(qry)
-	if err != nil {
-		ctx.Warn("Could not query count: %v", err)
-		return 0
+	database     *sql.DB
+	dialect      Dialect
+	quoting      bool
+	itype        reflect.Type
+	indexField   string
+	indexCol     string
+	version      Field
+	deleted      Field
+	auditing     *auditFields
+	upsertKey    []Field
+	chunkSize    int
+	generated    []Field
+	clock        Clock
+	actor        ActorFunc
+	sort         []Sort
+	countMode    CountMode
+	countLimit   int
+	queryTimeout time.Duration
+	writeTimeout time.Duration
+	retryPolicy  *RetryPolicy
+	schema       string
+	table        string
+	builder      EntityBuilder
+	behavior     interface{}
+}
+
+func (h *sqlHandler) quote(identifier string) string {
+	if h.quoting {
+		return h.dialect.Quote(identifier)
 	}
-	defer rows.Close()
+	return identifier
+}
 
-	if rows.Next() {
-		var result int
-		if err := rows.Scan(&result); err != nil {
-			ctx.Warn("Could not get count value: %v", err)
-			return 0
+func (h *sqlHandler) quoteAll(identifiers []string) []string {
+	result := make([]string, len(identifiers))
+	for i, id := range identifiers {
+		result[i] = h.quote(id)
+	}
+	return result
+}
+
+func (h *sqlHandler) columns() string {
+	return strings.Join(h.quoteAll(h.builder.SqlNames()), ", ")
+}
+
+func (h *sqlHandler) column(name string) string {
+	if fld, ok := h.builder.Field(name); ok {
+		return h.quote(fld.SQLName())
+	}
+	return h.quote(name)
+}
+
+func (h *sqlHandler) sqlNames(fields []Field) []string {
+	result := make([]string, len(fields))
+	for i, fld := range fields {
+		result[i] = h.quote(fld.SQLName())
+	}
+	return result
+}
+
+func (h *sqlHandler) filter(cond golik.Condition) (string, []interface{}, error) {
+	return newFilterBuilder(h.column).build(cond)
+}
+
+// baseContext returns the clove context as context.Context if supported.
+func baseContext(ctx golik.CloveContext) context.Context {
+	if tc, ok := ctx.(*txContext); ok {
+		return tc.c
+	}
+	if c, ok := ctx.(context.Context); ok {
+		return c
+	}
+	return context.Background()
+}
+
+// withTimeout derives the context of a database operation from the clove context,
+// so it is cancelled with the clove, limited by the given timeout if set.
+func withTimeout(ctx golik.CloveContext, timeout time.Duration) (context.Context, context.CancelFunc) {
+	parent := baseContext(ctx)
+	if timeout > 0 {
+		return context.WithTimeout(parent, timeout)
+	}
+	return context.WithCancel(parent)
+}
+
+func (h *sqlHandler) query(c context.Context, ctx golik.CloveContext, qry string, args ...interface{}) (*sql.Rows, error) {
+	qry = Rebind(h.dialect, qry)
+	ctx.Debug("Execute query: %v", qry)
+	if tc, ok := ctx.(*txContext); ok {
+		return tc.tx.QueryContext(c, qry, args...)
+	}
+	return h.database.QueryContext(c, qry, args...)
+}
+
+func (h *sqlHandler) prepare(c context.Context, ctx golik.CloveContext, tx *sql.Tx, ddl string) (*sql.Stmt, error) {
+	ddl = Rebind(h.dialect, ddl)
+	ctx.Debug("PrepareStatement: %v", ddl)
+	return tx.PrepareContext(c, ddl)
+}
+
+// orderBy renders the order by clause of the given sorts. The index field is always
+// appended as last criteria, so the order of pages is stable.
+func (h *sqlHandler) orderBy(sorts []Sort) (string, error) {
+	terms := make([]string, 0, len(sorts)+1)
+	indexed := false
+	for _, s := range sorts {
+		fld, ok := h.builder.Field(s.Field)
+		if !ok {
+			return "", fmt.Errorf("Unknown sort field %v", s.Field)
 		}
-		return result
+		if fld.SQLName() == h.indexCol {
+			indexed = true
+		}
+		terms = append(terms, h.dialect.OrderBy(h.quote(fld.SQLName()), s.Descending, s.Nulls))
+	}
+	if !indexed {
+		terms = append(terms, h.dialect.OrderBy(h.quote(h.indexCol), false, NullsDefault))
 	}
-	return 0
+	return strings.Join(terms, ", "), nil
+}
+
+func (h *sqlHandler) translate(err error) error {
+	return TranslateError(h.dialect, err)
 }
 
 func (h *sqlHandler) Filter(ctx golik.CloveContext, flt *golik.Filter) (*golik.Result, error) {
-	cond, err := flt.Condition()
+	result, err := h.find(ctx, &FilterCommand{Filter: flt})
+	if err != nil {
+		return nil, h.translate(err)
+	}
+	return result.Result, nil
+}
+
+// and combines the given where-clause with an additional condition.
+func and(where string, cond string) string {
+	if where == "" {
+		return "WHERE " + cond
+	}
+	return fmt.Sprintf("WHERE (%v) AND (%v)", strings.TrimPrefix(where, "WHERE "), cond)
+}
+
+func (h *sqlHandler) find(ctx golik.CloveContext, cmd *FilterCommand) (*FilterResult, error) {
+	c, cancel := withTimeout(ctx, h.queryTimeout)
+	defer cancel()
+
+	// commands without filter select all entities
+	var cond golik.Condition
+	flt := cmd.Filter
+	if flt == nil {
+		flt = &golik.Filter{}
+	} else {
+		var err error
+		if cond, err = flt.Condition(); err != nil {
+			return nil, err
+		}
+	}
+
+	// commands without sort use the sort of the connection pool like Filter
+	sorts := cmd.Sort
+	if len(sorts) == 0 {
+		sorts = h.sort
+	}
+
+	order, err := h.orderBy(sorts)
+	if err != nil {
+		return nil, err
+	}
+
+	where, args, err := h.filter(cond)
 	if err != nil {
 		return nil, err
 	}
+	where = h.excludeDeleted(where, cmd.IncludeDeleted)
+
+	mode, limit := h.countMode, h.countLimit
+	if cmd.Count != nil {
+		mode = *cmd.Count
+	}
+	if cmd.CountLimit > 0 {
+		limit = cmd.CountLimit
+	}
+	count := h.countAsync(c, ctx, mode, limit, where, args)
 
-	where, _ := NewFilter(cond)
-	count := h.count(ctx, where)
 	size := flt.Size
 	if size == 0 {
 		size = 10
 	}
-	to := flt.From + size
+
+	from := flt.From
+	var keys []keysetKey
+	if cmd.Keyset {
+		keys, err = h.keysetKeys(sorts)
+		if err != nil {
+			return nil, err
+		}
+		if cmd.Cursor != "" {
+			values, err := decodeCursor(cmd.Cursor, order, keys)
+			if err != nil {
+				return nil, err
+			}
+			pred, predArgs := h.keysetPredicate(keys, values)
+			where = and(where, pred)
+			args = append(args, predArgs...)
+		}
+		// one additional row tells whether there is a next page
+		from = 0
+		size++
+	}
+
 	filterQry := fmt.Sprintln(h.buildSelectAll(), where)
-	qry := fmt.Sprintf(baseFilterQuery, h.builder.ColumnQueryStr(), h.indexField, filterQry, flt.From+1, to)
-	ctx.Debug("Execute query: %v", qry)
+	qry, pageArgs := h.dialect.Paginate(h.columns(), filterQry, order, from, size)
 
-	rows, err := h.database.Query(qry)
+	rows, err := h.query(c, ctx, qry, append(args, pageArgs...)...)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
+	result, err := h.scan(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	cnt := <-count
+	if cnt.err != nil {
+		return nil, cnt.err
+	}
+
+	next := ""
+	if cmd.Keyset && len(result) == size {
+		result = result[:size-1]
+		next, err = encodeCursor(order, keys, result[len(result)-1])
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &FilterResult{
+		Result: &golik.Result{
+			From:   flt.From,
+			Size:   len(result),
+			Count:  cnt.count,
+			Result: result,
+		},
+		Cursor: next,
+	}, nil
+}
+
+func (h *sqlHandler) scan(rows *sql.Rows) ([]interface{}, error) {
 	result := make([]interface{}, 0)
 
 	vals := h.builder.ScanList()
@@ -128,52 +425,67 @@ func (h *sqlHandler) Filter(ctx golik.CloveContext, flt *golik.Filter) (*golik.R
 		result = append(result, res)
 	}
 
-	return &golik.Result{
-		From:   flt.From,
-		Size:   len(result),
-		Count:  count,
-		Result: result,
-	}, nil
+	return result, rows.Err()
 }
 
 func (h *sqlHandler) tablePath() string {
 	if h.schema == "" {
-		return h.table
+		return h.quote(h.table)
 	}
-	return fmt.Sprintf("%v.%v", h.schema, h.table)
+	return fmt.Sprintf("%v.%v", h.quote(h.schema), h.quote(h.table))
 }
 
-func (h *sqlHandler) buildInsert() string {
-	fields := h.builder.SqlNames()
-	result := make([]string, len(fields))
-	for i := range fields {
-		result[i] = "?"
+// parameters returns the placeholders of the values of the given fields, cast to the
+// column type of the `type=` tag option if set.
+func (h *sqlHandler) parameters(flds []Field) []string {
+	result := make([]string, len(flds))
+	for i, fld := range flds {
+		if t := fld.Tag().Type; t != "" && !strings.EqualFold(t, "json") {
+			result[i] = fmt.Sprintf("CAST(? AS %v)", t)
+		} else {
+			result[i] = "?"
+		}
 	}
+	return result
+}
 
-	return fmt.Sprintf("INSERT INTO %v (%v) VALUES (%v)", h.tablePath(), strings.Join(fields, ", "), strings.Join(result, ", "))
+func (h *sqlHandler) buildInsert(flds []Field) string {
+	fields := h.sqlNames(flds)
+	return fmt.Sprintf("INSERT INTO %v (%v) VALUES (%v)", h.tablePath(), strings.Join(fields, ", "), strings.Join(h.parameters(flds), ", "))
 }
 
+// Create inserts the entity of the command and populates it with the stored row,
+// including generated keys and defaults of the database.
 func (h *sqlHandler) Create(ctx golik.CloveContext, cmd *golik.CreateCommand) error {
-	tx, err := h.database.Begin()
+	return h.retry(ctx, "Create", func() error {
+		return h.create(ctx, cmd)
+	})
+}
+
+func (h *sqlHandler) create(ctx golik.CloveContext, cmd *golik.CreateCommand) error {
+	c, cancel := withTimeout(ctx, h.writeTimeout)
+	defer cancel()
+
+	tx, err := h.begin(c, ctx)
 	if err != nil {
 		return err
 	}
-	defer tx.Rollback()
+	defer tx.rollback()
 
-	ddl := h.buildInsert()
-	stmt, err := tx.Prepare(ddl)
-	ctx.Debug("PrepareStatement: %v", ddl)
+	h.initVersion(cmd.Entity)
+	_, omitted := h.audit(ctx, cmd.Entity, true)
+	fields := h.insertable(cmd.Entity, omitted...)
+	vals, err := h.builder.ValuesOf(cmd.Entity, fields)
 	if err != nil {
 		return err
 	}
-	defer stmt.Close()
+	fields, vals = h.writeActive(fields, vals)
 
-	_, err = stmt.Exec(h.builder.Values(cmd.Entity)...)
-	if err != nil {
+	if err := h.insert(c, ctx, tx.Tx, h.buildInsert(fields), vals, cmd.Entity); err != nil {
 		return err
 	}
 
-	if err := tx.Commit(); err != nil {
+	if err := tx.commit(); err != nil {
 		return err
 	}
 
@@ -181,13 +493,23 @@ func (h *sqlHandler) Create(ctx golik.CloveContext, cmd *golik.CreateCommand) er
 }
 
 func (h *sqlHandler) buildSelectAll() string {
-	return fmt.Sprintf("SELECT %v FROM %v", h.builder.ColumnQueryStr(), h.tablePath())
+	return fmt.Sprintf("SELECT %v FROM %v", h.columns(), h.tablePath())
 }
 
 func (h *sqlHandler) Read(ctx golik.CloveContext, cmd *golik.GetCommand) (interface{}, error) {
-	qry := fmt.Sprintf("%v WHERE %v = %v", h.buildSelectAll(), h.indexField, toSqlValue(cmd.Id))
-	ctx.Debug("Execute query: '%v'", qry)
-	rows, err := h.database.Query(qry)
+	result, err := h.read(ctx, cmd.Id, false)
+	return result, h.translate(err)
+}
+
+// read returns the entity of the given id, soft deleted entities are only
+// found with includeDeleted set.
+func (h *sqlHandler) read(ctx golik.CloveContext, id interface{}, includeDeleted bool) (interface{}, error) {
+	c, cancel := withTimeout(ctx, h.queryTimeout)
+	defer cancel()
+
+	where := h.excludeDeleted("WHERE "+h.quote(h.indexCol)+" = ?", includeDeleted)
+	qry := fmt.Sprintf("%v %v", h.buildSelectAll(), where)
+	rows, err := h.query(c, ctx, qry, id)
 	if err != nil {
 		return nil, err
 	}
@@ -208,90 +530,239 @@ func (h *sqlHandler) Read(ctx golik.CloveContext, cmd *golik.GetCommand) (interf
 		return result, nil
 	}
 
-	return nil, fmt.Errorf("Could not find entity with id %v", cmd.Id) // TODO define default errors
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return nil, notFound(id)
 }
 
-func (h *sqlHandler) buildUpdate() string {
-	fields := h.builder.SqlNames(h.indexField)
+// buildUpdate returns the update statement of the given fields. The version of versioned
+// entities is incremented by the statement, with checkVersion set the entity is only updated
+// if the version matches.
+func (h *sqlHandler) buildUpdate(flds []Field, checkVersion bool) string {
+	fields := h.sqlNames(flds)
+	params := h.parameters(flds)
 	result := make([]string, len(fields))
 	for i, f := range fields {
-		result[i] = f + " = ?"
+		result[i] = f + " = " + params[i]
+	}
+
+	where := h.quote(h.indexCol) + " = ?"
+	if h.version != nil {
+		col := h.quote(h.version.SQLName())
+		result = append(result, fmt.Sprintf("%v = %v + 1", col, col))
+		if checkVersion {
+			where += fmt.Sprintf(" AND %v = ?", col)
+		}
 	}
+	if h.deleted != nil {
+		where += " AND " + h.isActive()
+	}
+
+	return fmt.Sprintf("UPDATE %v SET %v WHERE %v", h.tablePath(), strings.Join(result, ", "), where)
+}
 
-	return fmt.Sprintf("UPDATE %v SET %v WHERE %v = ?", h.tablePath(), strings.Join(result, ", "), h.indexField)
+// writeExpects returns the fields never written by updates.
+func (h *sqlHandler) writeExpects() []string {
+	result := []string{h.indexField}
+	if h.version != nil {
+		result = append(result, h.version.Name())
+	}
+	if h.deleted != nil {
+		result = append(result, h.deleted.Name())
+	}
+	result = append(result, h.generatedNames()...)
+	return append(result, h.auditing.created()...)
 }
 
 func (h *sqlHandler) Update(ctx golik.CloveContext, cmd *golik.UpdateCommand) error {
-	if _, err := h.Read(ctx, golik.Get(cmd.Id)); err != nil {
+	return h.retry(ctx, "Update", func() error {
+		return h.update(ctx, cmd)
+	})
+}
+
+func (h *sqlHandler) update(ctx golik.CloveContext, cmd *golik.UpdateCommand) error {
+	_, omitted := h.audit(ctx, cmd.Entity, false)
+	fields := h.builder.Writable(cmd.Entity, append(h.writeExpects(), omitted...)...)
+	return h.write(ctx, cmd.Id, cmd.Entity, fields, h.version != nil)
+}
+
+// write updates the given fields of the entity with the given id. Missing entities are
+// detected by the affected rows, without reading the entity beforehand.
+func (h *sqlHandler) write(ctx golik.CloveContext, id interface{}, entity interface{}, fields []Field, checkVersion bool) error {
+	c, cancel := withTimeout(ctx, h.writeTimeout)
+	defer cancel()
+
+	tx, err := h.begin(c, ctx)
+	if err != nil {
 		return err
 	}
+	defer tx.rollback()
+
+	if len(fields) == 0 && h.version == nil {
+		if found, err := h.exists(c, ctx, tx.Tx, id); err != nil || !found {
+			if err == nil {
+				err = notFound(id)
+			}
+			return err
+		}
+		return tx.commit()
+	}
 
-	tx, err := h.database.Begin()
+	stmt, err := h.prepare(c, ctx, tx.Tx, h.buildUpdate(fields, checkVersion))
 	if err != nil {
 		return err
 	}
-	defer tx.Rollback()
+	defer stmt.Close()
 
-	ddl := h.buildUpdate()
-	stmt, err := tx.Prepare(ddl)
-	ctx.Debug("PrepareStatement: %v", ddl)
+	vals, err := h.builder.ValuesOf(entity, fields)
 	if err != nil {
 		return err
 	}
-	defer stmt.Close()
 
-	vals := append(h.builder.Values(cmd.Entity, h.indexField), cmd.Id)
-	_, err = stmt.Exec(vals...)
+	args := append(vals, id)
+	if checkVersion {
+		args = append(args, h.versionOf(entity))
+	}
+
+	res, err := stmt.ExecContext(c, args...)
 	if err != nil {
 		return err
 	}
 
-	if err := tx.Commit(); err != nil {
+	if affected, err := res.RowsAffected(); err == nil && affected == 0 {
+		if err := h.unchanged(c, ctx, tx.Tx, id, h.versionOf(entity), checkVersion); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.commit(); err != nil {
 		return err
 	}
 
+	if checkVersion {
+		h.setVersion(entity, h.versionOf(entity)+1)
+	}
+
 	return nil
 }
 
 func (h *sqlHandler) buildDelete() string {
-	return fmt.Sprintf("DELETE FROM %v WHERE %v = ?", h.tablePath(), h.indexField)
+	return fmt.Sprintf("DELETE FROM %v WHERE %v = ?", h.tablePath(), h.quote(h.indexCol))
 }
 
 func (h *sqlHandler) Delete(ctx golik.CloveContext, cmd *golik.DeleteCommand) (interface{}, error) {
-	entity, err := h.Read(ctx, golik.Get(cmd.Id))
-	if err != nil {
-		return nil, err
-	}
+	var result interface{}
+	err := h.retry(ctx, "Delete", func() error {
+		var err error
+		result, err = h.delete(ctx, cmd)
+		return err
+	})
+	return result, err
+}
 
-	tx, err := h.database.Begin()
-	if err != nil {
-		return nil, err
+func (h *sqlHandler) delete(ctx golik.CloveContext, cmd *golik.DeleteCommand) (interface{}, error) {
+	if h.deleted != nil {
+		return h.softDelete(ctx, cmd.Id)
 	}
-	defer tx.Rollback()
+	return h.purge(ctx, cmd.Id)
+}
 
-	ddl := h.buildDelete()
-	stmt, err := tx.Prepare(ddl)
-	ctx.Debug("PrepareStatement: %v", ddl)
+// purge removes the entity of the given id from the table, even if it is soft deleted.
+func (h *sqlHandler) purge(ctx golik.CloveContext, id interface{}) (interface{}, error) {
+	c, cancel := withTimeout(ctx, h.writeTimeout)
+	defer cancel()
+
+	tx, err := h.begin(c, ctx)
 	if err != nil {
 		return nil, err
 	}
-	defer stmt.Close()
+	defer tx.rollback()
 
-	_, err = stmt.Exec(cmd.Id)
+	entity, err := h.remove(c, ctx, tx.Tx, id)
 	if err != nil {
 		return nil, err
 	}
 
-	if err := tx.Commit(); err != nil {
+	if err := tx.commit(); err != nil {
 		return nil, err
 	}
 
 	return entity, nil
 }
 
+// replyWrite replies the result of the given write operation, which is retried on failure.
+func (h *sqlHandler) replyWrite(ctx golik.CloveContext, msg golik.Message, op string, fn func() (interface{}, error)) {
+	var result interface{}
+	err := h.retry(ctx, op, func() error {
+		var err error
+		result, err = fn()
+		return err
+	})
+	if err != nil {
+		msg.Reply(err)
+		return
+	}
+	msg.Reply(result)
+}
+
 func (h *sqlHandler) OrElse(ctx golik.CloveContext, msg golik.Message) {
+	switch cmd := msg.Content().(type) {
+	case *FilterCommand:
+		result, err := h.find(ctx, cmd)
+		if err != nil {
+			msg.Reply(h.translate(err))
+			return
+		}
+		if cmd.Keyset {
+			msg.Reply(result)
+			return
+		}
+		msg.Reply(result.Result)
+		return
+	case *ReadCommand:
+		result, err := h.read(ctx, cmd.Id, cmd.IncludeDeleted)
+		if err != nil {
+			msg.Reply(h.translate(err))
+			return
+		}
+		msg.Reply(result)
+		return
+	case *RestoreCommand:
+		h.replyWrite(ctx, msg, "Restore", func() (interface{}, error) {
+			return h.restore(ctx, cmd.Id)
+		})
+		return
+	case *BatchCreateCommand:
+		msg.Reply(h.batchCreate(ctx, cmd))
+		return
+	case *BatchUpdateCommand:
+		msg.Reply(h.batchUpdate(ctx, cmd))
+		return
+	case *BatchDeleteCommand:
+		msg.Reply(h.batchDelete(ctx, cmd))
+		return
+	case *UpsertCommand:
+		h.replyWrite(ctx, msg, "Upsert", func() (interface{}, error) {
+			return h.upsert(ctx, cmd)
+		})
+		return
+	case *PatchCommand:
+		h.replyWrite(ctx, msg, "Patch", func() (interface{}, error) {
+			return h.patch(ctx, cmd)
+		})
+		return
+	case *PurgeCommand:
+		h.replyWrite(ctx, msg, "Purge", func() (interface{}, error) {
+			return h.purge(ctx, cmd.Id)
+		})
+		return
+	}
+
 	if h.behavior != nil {
 		ctx.AddOption("sql.database", h.database)
+		ctx.AddOption("sql.dialect", h.dialect)
 		ctx.AddOption("sql.schema", h.schema)
 		ctx.AddOption("sql.table", h.table)
 		golik.CallBehavior(ctx, msg, h.behavior)
@@ -0,0 +1,34 @@
+package sql
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestWithTimeout(t *testing.T) {
+	c, cancel := withTimeout(nil, time.Minute)
+	defer cancel()
+	if deadline, ok := c.Deadline(); !ok || time.Until(deadline) > time.Minute {
+		t.Errorf("deadline = %v, %v, want within a minute", deadline, ok)
+	}
+
+	c, cancel = withTimeout(nil, 0)
+	if _, ok := c.Deadline(); ok {
+		t.Error("expected no deadline without timeout")
+	}
+	cancel()
+	if c.Err() != context.Canceled {
+		t.Errorf("err = %v, want canceled", c.Err())
+	}
+
+	parent, cancelParent := context.WithCancel(context.Background())
+	c, cancel = withTimeout(&txContext{c: parent}, time.Minute)
+	defer cancel()
+	cancelParent()
+	select {
+	case <-c.Done():
+	case <-time.After(time.Second):
+		t.Error("expected the context to be cancelled with its parent")
+	}
+}
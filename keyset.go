@@ -0,0 +1,138 @@
+package sql
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+var errInvalidCursor = errors.New("Invalid cursor")
+
+type keysetKey struct {
+	field      Field
+	descending bool
+}
+
+// cursor is the decoded form of the opaque keyset cursor. The order is part
+// of the cursor, so a cursor could not be used with a different sort.
+type cursor struct {
+	Order string            `json:"o"`
+	Keys  []json.RawMessage `json:"k"`
+}
+
+// nullable tells whether the column of the given field may hold NULL values.
+func nullable(fld Field) bool {
+	ftype := fld.Field().Type
+	if ftype.Kind() == reflect.Ptr || fld.Tag().Nullable {
+		return true
+	}
+	for _, t := range nulltypes {
+		if ftype == t {
+			return true
+		}
+	}
+	return false
+}
+
+// keysetKeys returns the fields identifying the position of a row in the given order.
+// Nullable fields are rejected, rows with NULL keys could not be compared to the cursor.
+func (h *sqlHandler) keysetKeys(sorts []Sort) ([]keysetKey, error) {
+	result := make([]keysetKey, 0, len(sorts)+1)
+	indexed := false
+	for _, s := range sorts {
+		fld, ok := h.builder.Field(s.Field)
+		if !ok {
+			return nil, fmt.Errorf("Unknown sort field %v", s.Field)
+		}
+		if nullable(fld) {
+			return nil, fmt.Errorf("Keyset pagination does not support the nullable sort field %v", s.Field)
+		}
+		if fld.SQLName() == h.indexCol {
+			indexed = true
+		}
+		result = append(result, keysetKey{field: fld, descending: s.Descending})
+	}
+	if !indexed {
+		fld, ok := h.builder.Field(h.indexField)
+		if !ok {
+			return nil, fmt.Errorf("Unknown index field %v", h.indexField)
+		}
+		result = append(result, keysetKey{field: fld})
+	}
+	return result, nil
+}
+
+func encodeCursor(order string, keys []keysetKey, entity interface{}) (string, error) {
+	elemvalue := reflect.ValueOf(entity).Elem()
+	c := cursor{Order: order, Keys: make([]json.RawMessage, len(keys))}
+	for i, k := range keys {
+		fldvalue, ok := fieldByIndex(elemvalue, k.field.Field().Index, false)
+		if !ok || (fldvalue.Kind() == reflect.Ptr && fldvalue.IsNil()) {
+			return "", fmt.Errorf("Keyset pagination does not support NULL values of %v", k.field.Name())
+		}
+		data, err := json.Marshal(fldvalue.Interface())
+		if err != nil {
+			return "", err
+		}
+		c.Keys[i] = data
+	}
+
+	data, err := json.Marshal(c)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+func decodeCursor(value string, order string, keys []keysetKey) ([]interface{}, error) {
+	data, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, errInvalidCursor
+	}
+	c := cursor{}
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, errInvalidCursor
+	}
+	if c.Order != order || len(c.Keys) != len(keys) {
+		return nil, fmt.Errorf("%v: cursor does not match sort order", errInvalidCursor)
+	}
+
+	result := make([]interface{}, len(keys))
+	for i, k := range keys {
+		val := reflect.New(k.field.Field().Type)
+		if err := json.Unmarshal(c.Keys[i], val.Interface()); err != nil {
+			return nil, errInvalidCursor
+		}
+		dv, err := k.field.Converter().DriverValue(val.Elem())
+		if err != nil {
+			return nil, err
+		}
+		result[i] = dv
+	}
+	return result, nil
+}
+
+// keysetPredicate selects all rows after the given key values, e.g. for the keys a, b:
+// (a > ?) OR (a = ? AND b > ?)
+func (h *sqlHandler) keysetPredicate(keys []keysetKey, values []interface{}) (string, []interface{}) {
+	terms := make([]string, len(keys))
+	args := make([]interface{}, 0)
+	for i, k := range keys {
+		conds := make([]string, 0, i+1)
+		for j := 0; j < i; j++ {
+			conds = append(conds, h.quote(keys[j].field.SQLName())+" = ?")
+			args = append(args, values[j])
+		}
+		op := " > ?"
+		if k.descending {
+			op = " < ?"
+		}
+		conds = append(conds, h.quote(k.field.SQLName())+op)
+		args = append(args, values[i])
+		terms[i] = "(" + strings.Join(conds, " AND ") + ")"
+	}
+	return strings.Join(terms, " OR "), args
+}
@@ -0,0 +1,90 @@
+package sql
+
+import (
+	"database/sql"
+	"reflect"
+	"testing"
+)
+
+type testRow struct {
+	ID   int64
+	Name string
+}
+
+func testKeys(t *testing.T, names ...string) []keysetKey {
+	builder := NewEntityBuilder(reflect.TypeOf(testRow{}))
+	keys := make([]keysetKey, len(names))
+	for i, name := range names {
+		fld, ok := builder.Field(name)
+		if !ok {
+			t.Fatalf("Unknown field %v", name)
+		}
+		keys[i] = keysetKey{field: fld}
+	}
+	return keys
+}
+
+func TestCursor(t *testing.T) {
+	keys := testKeys(t, "Name", "ID")
+	value, err := encodeCursor("NAME ASC, ID ASC", keys, &testRow{ID: 42, Name: "x"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := decodeCursor(value, "NAME ASC, ID ASC", keys)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []interface{}{"x", int64(42)}; !reflect.DeepEqual(got, want) {
+		t.Errorf("decodeCursor = %#v, want %#v", got, want)
+	}
+
+	tests := []struct {
+		name  string
+		value string
+		order string
+		keys  []keysetKey
+	}{
+		{"no base64", "%%%", "NAME ASC, ID ASC", keys},
+		{"no json", "bm8", "NAME ASC, ID ASC", keys},
+		{"other order", value, "NAME DESC, ID ASC", keys},
+		{"other keys", value, "NAME ASC, ID ASC", testKeys(t, "ID")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := decodeCursor(tt.value, tt.order, tt.keys); err == nil {
+				t.Error("expected invalid cursor")
+			}
+		})
+	}
+}
+
+type testNullableRow struct {
+	ID       int64
+	Name     string
+	Nickname *string
+	Email    sql.NullString
+	Phone    string `sql:",nullable"`
+}
+
+func TestKeysetKeys(t *testing.T) {
+	h, err := NewHandler(&HandlerSettings{Database: &sql.DB{}, Type: reflect.TypeOf(testNullableRow{})})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sh := h.(*sqlHandler)
+
+	keys, err := sh.keysetKeys([]Sort{Desc("name")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(keys) != 2 || keys[0].field.Name() != "Name" || !keys[0].descending || keys[1].field.Name() != "ID" {
+		t.Errorf("keys = %+v, want name descending and the index field", keys)
+	}
+
+	for _, name := range []string{"nickname", "email", "phone"} {
+		if _, err := sh.keysetKeys([]Sort{Asc(name)}); err == nil {
+			t.Errorf("expected nullable sort field %v to be rejected", name)
+		}
+	}
+}
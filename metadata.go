@@ -0,0 +1,144 @@
+package sql
+
+import (
+	"reflect"
+	"sort"
+	"sync"
+)
+
+type fieldMeta struct {
+	field  reflect.StructField
+	tag    FieldTag
+	name   string
+	prefix string
+}
+
+// typeRegistry caches the exported fields and parsed tags of struct types,
+// so the reflection walk over a type happens only once per process.
+var typeRegistry = struct {
+	sync.RWMutex
+	types map[reflect.Type][]fieldMeta
+}{types: make(map[reflect.Type][]fieldMeta)}
+
+func structFields(etype reflect.Type) []fieldMeta {
+	typeRegistry.RLock()
+	result, ok := typeRegistry.types[etype]
+	typeRegistry.RUnlock()
+	if ok {
+		return result
+	}
+
+	result = walkStruct(etype, nil, "", "", map[reflect.Type]bool{etype: true})
+
+	typeRegistry.Lock()
+	defer typeRegistry.Unlock()
+	typeRegistry.types[etype] = result
+	return result
+}
+
+// flattenable reports whether the fields of the given struct type are mapped to columns
+// of the surrounding entity instead of the struct itself being a single column.
+func flattenable(ftype reflect.Type, tag FieldTag) bool {
+	if ftype.Kind() == reflect.Ptr {
+		ftype = ftype.Elem()
+	}
+	if ftype.Kind() != reflect.Struct || ftype == timetype || tag.JSON || isJSONType(ftype) {
+		return false
+	}
+	return !reflect.PtrTo(ftype).Implements(scannertype)
+}
+
+// walkStruct collects the fields of etype. Embedded structs are flattened with go's
+// promotion rules, named structs are flattened if tagged with inline.
+func walkStruct(etype reflect.Type, index []int, name string, prefix string, visited map[reflect.Type]bool) []fieldMeta {
+	result := make([]fieldMeta, 0, etype.NumField())
+	nested := make([][]fieldMeta, 0)
+	names := make(map[string]bool)
+
+	for i := 0; i < etype.NumField(); i++ {
+		field := etype.Field(i)
+		tag := ParseFieldTag(field.Tag.Get("sql"))
+		if tag.Ignore {
+			continue
+		}
+		field.Index = append(append([]int{}, index...), i)
+
+		embedded := field.Anonymous && tag.Column == "" && flattenable(field.Type, tag)
+		inline := !field.Anonymous && tag.Inline && flattenable(field.Type, tag)
+		if embedded || inline {
+			stype := field.Type
+			if stype.Kind() == reflect.Ptr {
+				if field.PkgPath != "" {
+					// nil pointers of unexported embedded structs could not be allocated
+					continue
+				}
+				stype = stype.Elem()
+			}
+			if visited[stype] {
+				continue
+			}
+			visited[stype] = true
+			if embedded {
+				nested = append(nested, walkStruct(stype, field.Index, name, prefix+tag.Prefix, visited))
+			} else {
+				nested = append(nested, walkStruct(stype, field.Index, name+field.Name+".", prefix+tag.Prefix, visited))
+			}
+			delete(visited, stype)
+			continue
+		}
+
+		if field.PkgPath != "" {
+			continue
+		}
+		names[field.Name] = true
+		result = append(result, fieldMeta{
+			field:  field,
+			tag:    tag,
+			name:   name + field.Name,
+			prefix: prefix,
+		})
+	}
+
+	// shallower fields hide fields of embedded structs with the same name
+	for _, fields := range nested {
+		for _, meta := range fields {
+			if !names[meta.name] {
+				names[meta.name] = true
+				result = append(result, meta)
+			}
+		}
+	}
+
+	// keep the declaration order like encoding/json, promoted fields take the place of their struct
+	sort.Slice(result, func(i, j int) bool {
+		return lessIndex(result[i].field.Index, result[j].field.Index)
+	})
+	return result
+}
+
+func lessIndex(a []int, b []int) bool {
+	for k := 0; k < len(a) && k < len(b); k++ {
+		if a[k] != b[k] {
+			return a[k] < b[k]
+		}
+	}
+	return len(a) < len(b)
+}
+
+// fieldByIndex returns the field of the given struct value. Nil pointers on the
+// way are allocated if alloc is set, otherwise false is returned.
+func fieldByIndex(v reflect.Value, index []int, alloc bool) (reflect.Value, bool) {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				if !alloc {
+					return reflect.Value{}, false
+				}
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+	return v, true
+}
@@ -0,0 +1,78 @@
+package sql
+
+import (
+	"database/sql"
+	"reflect"
+	"testing"
+)
+
+type testModel struct {
+	ID   int64
+	Name string
+}
+
+type testAddress struct {
+	Street string
+}
+
+type testCustomer struct {
+	testModel
+	Name    string
+	Home    testAddress `sql:",inline,prefix=home_"`
+	Ignored string      `sql:"-"`
+	hidden  string
+}
+
+func TestWalkStruct(t *testing.T) {
+	type field struct {
+		name   string
+		index  []int
+		prefix string
+	}
+	want := []field{
+		{"ID", []int{0, 0}, ""},
+		{"Name", []int{1}, ""},
+		{"Home.Street", []int{2, 0}, "home_"},
+	}
+
+	metas := structFields(reflect.TypeOf(testCustomer{}))
+	got := make([]field, len(metas))
+	for i, meta := range metas {
+		got[i] = field{meta.name, meta.field.Index, meta.prefix}
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("fields = %+v, want %+v", got, want)
+	}
+}
+
+func TestDefaultIndexField(t *testing.T) {
+	h, err := NewHandler(&HandlerSettings{Database: &sql.DB{}, Type: reflect.TypeOf(testCustomer{})})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if col := h.(*sqlHandler).indexCol; col != "ID" {
+		t.Errorf("index column = %v, want ID", col)
+	}
+}
+
+func TestFieldCache(t *testing.T) {
+	etype := reflect.TypeOf(testCustomer{})
+	first, second := structFields(etype), structFields(etype)
+	if len(first) == 0 || &first[0] != &second[0] {
+		t.Error("expected the cached fields of the type")
+	}
+
+	builder := NewEntityBuilder(etype)
+	fields := builder.Fields("name")
+	if len(fields) != 2 || &fields[0] != &builder.Fields("NAME")[0] {
+		t.Errorf("expected the cached fields without name, got %v fields", len(fields))
+	}
+
+	if name := builder.Fields()[0].SQLName(); name != "ID" {
+		t.Errorf("column = %v, want ID", name)
+	}
+	builder.WithNaming(LowerCase)
+	if name := builder.Fields()[0].SQLName(); name != "id" {
+		t.Errorf("column after naming change = %v, want id", name)
+	}
+}
@@ -0,0 +1,135 @@
+package sql
+
+import (
+	"strings"
+	"unicode"
+)
+
+// NamingStrategy derives table and column names from go type and field names.
+type NamingStrategy interface {
+	TableName(string) string
+	ColumnName(string) string
+}
+
+type namingFunc func(string) string
+
+func (f namingFunc) TableName(name string) string {
+	return f(name)
+}
+
+func (f namingFunc) ColumnName(name string) string {
+	return f(name)
+}
+
+var (
+	// UpperCase maps CreatedAt to CREATEDAT
+	UpperCase NamingStrategy = namingFunc(strings.ToUpper)
+	// LowerCase maps CreatedAt to createdat
+	LowerCase NamingStrategy = namingFunc(strings.ToLower)
+	// SnakeCase maps CreatedAt to created_at
+	SnakeCase NamingStrategy = namingFunc(func(name string) string {
+		return strings.ToLower(strings.Join(splitWords(name), "_"))
+	})
+	// ScreamingSnakeCase maps CreatedAt to CREATED_AT
+	ScreamingSnakeCase NamingStrategy = namingFunc(func(name string) string {
+		return strings.ToUpper(strings.Join(splitWords(name), "_"))
+	})
+	// CamelCase maps CreatedAt to createdAt
+	CamelCase NamingStrategy = namingFunc(func(name string) string {
+		words := splitWords(name)
+		for i, w := range words {
+			if i == 0 {
+				words[i] = strings.ToLower(w)
+			} else {
+				words[i] = strings.ToUpper(w[:1]) + strings.ToLower(w[1:])
+			}
+		}
+		return strings.Join(words, "")
+	})
+	// Identity keeps names as they are
+	Identity NamingStrategy = namingFunc(func(name string) string { return name })
+)
+
+var namingStrategies = map[string]NamingStrategy{
+	"upper":           UpperCase,
+	"lower":           LowerCase,
+	"snake":           SnakeCase,
+	"snake_case":      SnakeCase,
+	"screaming_snake": ScreamingSnakeCase,
+	"camel":           CamelCase,
+	"camelcase":       CamelCase,
+	"identity":        Identity,
+}
+
+// LookupNamingStrategy returns the built-in naming strategy of the given name.
+func LookupNamingStrategy(name string) (NamingStrategy, bool) {
+	ns, ok := namingStrategies[strings.ToLower(name)]
+	return ns, ok
+}
+
+// NamingAffixes are added to the names produced by a naming strategy, e.g. a "T_" table prefix.
+type NamingAffixes struct {
+	TablePrefix  string
+	TableSuffix  string
+	ColumnPrefix string
+	ColumnSuffix string
+}
+
+func (a NamingAffixes) empty() bool {
+	return a == NamingAffixes{}
+}
+
+// WithAffixes wraps the given strategy, so that all names get the given prefixes and suffixes.
+func WithAffixes(strategy NamingStrategy, affixes NamingAffixes) NamingStrategy {
+	if affixes.empty() {
+		return strategy
+	}
+	return &affixedNaming{
+		strategy: strategy,
+		affixes:  affixes,
+	}
+}
+
+type affixedNaming struct {
+	strategy NamingStrategy
+	affixes  NamingAffixes
+}
+
+func (n *affixedNaming) TableName(name string) string {
+	return n.affixes.TablePrefix + n.strategy.TableName(name) + n.affixes.TableSuffix
+}
+
+func (n *affixedNaming) ColumnName(name string) string {
+	return n.affixes.ColumnPrefix + n.strategy.ColumnName(name) + n.affixes.ColumnSuffix
+}
+
+// splitWords splits go identifiers like HTTPServerID into HTTP, Server and ID.
+func splitWords(name string) []string {
+	runes := []rune(name)
+	words := make([]string, 0)
+	start := 0
+	for i := 1; i < len(runes); i++ {
+		prev, cur := runes[i-1], runes[i]
+		switch {
+		case cur == '_' || cur == '-':
+			if start < i {
+				words = append(words, string(runes[start:i]))
+			}
+			start = i + 1
+		case unicode.IsUpper(cur) && (unicode.IsLower(prev) || unicode.IsDigit(prev)):
+			if start < i {
+				words = append(words, string(runes[start:i]))
+			}
+			start = i
+		case unicode.IsUpper(prev) && unicode.IsUpper(cur) && i+1 < len(runes) && unicode.IsLower(runes[i+1]):
+			if start < i {
+				words = append(words, string(runes[start:i]))
+			}
+			start = i
+		}
+	}
+	if start < len(runes) {
+		words = append(words, string(runes[start:]))
+	}
+	return words
+}
@@ -0,0 +1,62 @@
+package sql
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSplitWords(t *testing.T) {
+	tests := []struct {
+		name string
+		want []string
+	}{
+		{"Name", []string{"Name"}},
+		{"firstName", []string{"first", "Name"}},
+		{"HTTPServerID", []string{"HTTP", "Server", "ID"}},
+		{"ID", []string{"ID"}},
+		{"Address2Street", []string{"Address2", "Street"}},
+		{"snake_case-name", []string{"snake", "case", "name"}},
+	}
+
+	for _, tt := range tests {
+		if got := splitWords(tt.name); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("splitWords(%q) = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestNamingStrategies(t *testing.T) {
+	tests := []struct {
+		strategy NamingStrategy
+		name     string
+		table    string
+		column   string
+	}{
+		{UpperCase, "CreatedAt", "CREATEDAT", "CREATEDAT"},
+		{LowerCase, "CreatedAt", "createdat", "createdat"},
+		{SnakeCase, "HTTPServerID", "http_server_id", "http_server_id"},
+		{ScreamingSnakeCase, "CreatedAt", "CREATED_AT", "CREATED_AT"},
+		{CamelCase, "HTTPServerID", "httpServerId", "httpServerId"},
+		{Identity, "CreatedAt", "CreatedAt", "CreatedAt"},
+		{WithAffixes(SnakeCase, NamingAffixes{TablePrefix: "t_", TableSuffix: "s", ColumnPrefix: "c_"}), "OrderItem", "t_order_items", "c_order_item"},
+		{WithAffixes(UpperCase, NamingAffixes{}), "Name", "NAME", "NAME"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.strategy.TableName(tt.name); got != tt.table {
+			t.Errorf("TableName(%q) = %q, want %q", tt.name, got, tt.table)
+		}
+		if got := tt.strategy.ColumnName(tt.name); got != tt.column {
+			t.Errorf("ColumnName(%q) = %q, want %q", tt.name, got, tt.column)
+		}
+	}
+}
+
+func TestLookupNamingStrategy(t *testing.T) {
+	if ns, ok := LookupNamingStrategy("Snake_Case"); !ok || ns.ColumnName("FirstName") != "first_name" {
+		t.Errorf("LookupNamingStrategy(Snake_Case) = %v, %v, want snake case", ns, ok)
+	}
+	if _, ok := LookupNamingStrategy("kebab"); ok {
+		t.Error("expected unknown naming strategy")
+	}
+}
@@ -0,0 +1,168 @@
+package sql
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ioswarm/golik"
+)
+
+func optionString(options map[string]interface{}, key string, def string) string {
+	if v, ok := options[key]; ok && v != nil {
+		return fmt.Sprint(v)
+	}
+	return def
+}
+
+func optionBool(options map[string]interface{}, key string, def bool) bool {
+	v, ok := options[key]
+	if !ok || v == nil {
+		return def
+	}
+	switch b := v.(type) {
+	case bool:
+		return b
+	default:
+		if result, err := strconv.ParseBool(fmt.Sprint(b)); err == nil {
+			return result
+		}
+		return def
+	}
+}
+
+func optionDialect(options map[string]interface{}, key string, def Dialect) (Dialect, error) {
+	v, ok := options[key]
+	if !ok || v == nil {
+		return def, nil
+	}
+	switch d := v.(type) {
+	case Dialect:
+		return d, nil
+	default:
+		if result, ok := LookupDialect(fmt.Sprint(d)); ok {
+			return result, nil
+		}
+		return nil, fmt.Errorf("Unknown sql dialect %v", d)
+	}
+}
+
+func optionNaming(options map[string]interface{}, key string, def NamingStrategy) (NamingStrategy, error) {
+	v, ok := options[key]
+	if !ok || v == nil {
+		return def, nil
+	}
+	switch n := v.(type) {
+	case NamingStrategy:
+		return n, nil
+	default:
+		if result, ok := LookupNamingStrategy(fmt.Sprint(n)); ok {
+			return result, nil
+		}
+		return nil, fmt.Errorf("Unknown naming strategy %v", n)
+	}
+}
+
+func optionSort(options map[string]interface{}, key string) ([]Sort, error) {
+	v, ok := options[key]
+	if !ok || v == nil {
+		return nil, nil
+	}
+	switch s := v.(type) {
+	case []Sort:
+		return s, nil
+	case Sort:
+		return []Sort{s}, nil
+	default:
+		return ParseSort(fmt.Sprint(s))
+	}
+}
+
+// optionStrings reads a string slice or a comma separated string.
+func optionStrings(options map[string]interface{}, key string) []string {
+	v, ok := options[key]
+	if !ok || v == nil {
+		return nil
+	}
+	switch s := v.(type) {
+	case []string:
+		return s
+	default:
+		result := make([]string, 0)
+		for _, part := range strings.Split(fmt.Sprint(s), ",") {
+			if part = strings.TrimSpace(part); part != "" {
+				result = append(result, part)
+			}
+		}
+		return result
+	}
+}
+
+func optionInt(options map[string]interface{}, key string, def int) int {
+	v, ok := options[key]
+	if !ok || v == nil {
+		return def
+	}
+	switch i := v.(type) {
+	case int:
+		return i
+	default:
+		if result, err := strconv.Atoi(fmt.Sprint(i)); err == nil {
+			return result
+		}
+		return def
+	}
+}
+
+func optionCountMode(options map[string]interface{}, key string, def string) (CountMode, error) {
+	v, ok := options[key]
+	if !ok || v == nil {
+		return ParseCountMode(def)
+	}
+	switch m := v.(type) {
+	case CountMode:
+		return m, nil
+	default:
+		return ParseCountMode(fmt.Sprint(m))
+	}
+}
+
+// optionDuration reads a time.Duration or a number of seconds like the settings do.
+func optionDuration(options map[string]interface{}, key string, def time.Duration) time.Duration {
+	v, ok := options[key]
+	if !ok || v == nil {
+		return def
+	}
+	switch d := v.(type) {
+	case time.Duration:
+		return d
+	case int:
+		return time.Duration(d) * time.Second
+	default:
+		if result, err := time.ParseDuration(fmt.Sprint(d)); err == nil {
+			return result
+		}
+		return def
+	}
+}
+
+func optionClock(options map[string]interface{}, key string) Clock {
+	switch c := options[key].(type) {
+	case Clock:
+		return c
+	case func() time.Time:
+		return c
+	}
+	return nil
+}
+
+func optionActor(options map[string]interface{}, key string) ActorFunc {
+	switch a := options[key].(type) {
+	case ActorFunc:
+		return a
+	case func(golik.CloveContext) string:
+		return a
+	}
+	return nil
+}
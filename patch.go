@@ -0,0 +1,148 @@
+package sql
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+
+	"github.com/ioswarm/golik"
+)
+
+// patchFields validates the given names against the fields of the entity. Names of fields
+// that are not written by updates are rejected, except for the version field which
+// enables the version check.
+func (h *sqlHandler) patchFields(names []string) ([]Field, bool, error) {
+	if len(names) == 0 {
+		return nil, false, errors.New("Patch does not contain any field")
+	}
+
+	result := make([]Field, 0, len(names))
+	checkVersion := false
+	for _, name := range names {
+		fld, ok := h.builder.Field(name)
+		if !ok {
+			return nil, false, fmt.Errorf("Unknown field %v", name)
+		}
+		if fld == h.version {
+			checkVersion = true
+			continue
+		}
+		if fld.Tag().ReadOnly {
+			return nil, false, fmt.Errorf("Field %v is readonly", name)
+		}
+		for _, expect := range h.writeExpects() {
+			if matchesField(fld, expect) {
+				return nil, false, fmt.Errorf("Field %v could not be patched", name)
+			}
+		}
+		result = append(result, fld)
+	}
+	return result, checkVersion, nil
+}
+
+// patchValue converts the given value to the type of the field. Values not assignable to the
+// field are converted like json, so decoded numbers must fit the field without losing precision
+// and times are parsed from RFC 3339 strings.
+func patchValue(fld Field, value interface{}) (reflect.Value, error) {
+	ftype := fld.Field().Type
+	if value == nil {
+		return reflect.Zero(ftype), nil
+	}
+
+	val := reflect.ValueOf(value)
+	if val.Type().AssignableTo(ftype) {
+		return val, nil
+	}
+
+	data, err := json.Marshal(value)
+	if err != nil {
+		return reflect.ValueOf(nil), fmt.Errorf("Could not assign %T to field %v: %w", value, fld.Name(), err)
+	}
+	result := reflect.New(ftype)
+	if err := json.Unmarshal(data, result.Interface()); err != nil {
+		return reflect.ValueOf(nil), fmt.Errorf("Could not assign %T to field %v: %w", value, fld.Name(), err)
+	}
+	return result.Elem(), nil
+}
+
+// patchEntity returns the entity holding the values of the given patch.
+func (h *sqlHandler) patchEntity(cmd *PatchCommand) (interface{}, []string, error) {
+	if cmd.Values == nil {
+		if cmd.Entity == nil {
+			return nil, nil, errors.New("Patch requires an entity or values")
+		}
+		return cmd.Entity, cmd.Fields, nil
+	}
+
+	entity := reflect.New(h.itype)
+	names := make([]string, 0, len(cmd.Values))
+	for name, value := range cmd.Values {
+		fld, ok := h.builder.Field(name)
+		if !ok {
+			return nil, nil, fmt.Errorf("Unknown field %v", name)
+		}
+		val, err := patchValue(fld, value)
+		if err != nil {
+			return nil, nil, err
+		}
+		fldvalue, _ := fieldByIndex(entity.Elem(), fld.Field().Index, true)
+		fldvalue.Set(val)
+		names = append(names, name)
+	}
+	return entity.Interface(), names, nil
+}
+
+// patch updates the fields of the given patch and returns the updated entity.
+func (h *sqlHandler) patch(ctx golik.CloveContext, cmd *PatchCommand) (interface{}, error) {
+	entity, names, err := h.patchEntity(cmd)
+	if err != nil {
+		return nil, err
+	}
+
+	fields, checkVersion, err := h.patchFields(names)
+	if err != nil {
+		return nil, err
+	}
+
+	written, omitted := h.audit(ctx, entity, false)
+	for _, fld := range written {
+		if !containsField(fields, fld) {
+			fields = append(fields, fld)
+		}
+	}
+	fields = withoutFields(fields, omitted)
+
+	if err := h.write(ctx, cmd.Id, entity, fields, checkVersion); err != nil {
+		return nil, err
+	}
+
+	return h.read(ctx, cmd.Id, false)
+}
+
+func containsField(fields []Field, fld Field) bool {
+	for _, f := range fields {
+		if f == fld {
+			return true
+		}
+	}
+	return false
+}
+
+// withoutFields returns the given fields except the fields of the given names.
+func withoutFields(fields []Field, names []string) []Field {
+	result := make([]Field, 0, len(fields))
+	for _, fld := range fields {
+		omit := false
+		for _, name := range names {
+			if matchesField(fld, name) {
+				omit = true
+				break
+			}
+		}
+		if !omit {
+			result = append(result, fld)
+		}
+	}
+	return result
+}
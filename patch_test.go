@@ -0,0 +1,66 @@
+package sql
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+type testPatch struct {
+	Count int
+	Small int8
+	Ratio float64
+	Name  string
+	At    time.Time
+	Until *time.Time
+	Limit *int64
+}
+
+func TestPatchValue(t *testing.T) {
+	at := time.Date(2020, 5, 17, 10, 30, 0, 0, time.UTC)
+	limit := int64(7)
+
+	tests := []struct {
+		field string
+		value interface{}
+		want  interface{}
+		err   bool
+	}{
+		{field: "Count", value: 3, want: 3},
+		{field: "Count", value: float64(2), want: 2},
+		{field: "Count", value: 2.9, err: true},
+		{field: "Small", value: float64(300), err: true},
+		{field: "Count", value: "2", err: true},
+		{field: "Ratio", value: 2, want: float64(2)},
+		{field: "Name", value: "x", want: "x"},
+		{field: "Name", value: float64(65), err: true},
+		{field: "At", value: at, want: at},
+		{field: "At", value: "2020-05-17T10:30:00Z", want: at},
+		{field: "At", value: "yesterday", err: true},
+		{field: "Until", value: "2020-05-17T10:30:00Z", want: &at},
+		{field: "Until", value: nil, want: (*time.Time)(nil)},
+		{field: "Limit", value: float64(7), want: &limit},
+	}
+
+	builder := NewEntityBuilder(reflect.TypeOf(testPatch{}))
+	for _, tt := range tests {
+		fld, ok := builder.Field(tt.field)
+		if !ok {
+			t.Fatalf("Unknown field %v", tt.field)
+		}
+		got, err := patchValue(fld, tt.value)
+		if tt.err {
+			if err == nil {
+				t.Errorf("patchValue(%v, %#v) = %v, expected error", tt.field, tt.value, got)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("patchValue(%v, %#v) failed: %v", tt.field, tt.value, err)
+			continue
+		}
+		if !reflect.DeepEqual(got.Interface(), tt.want) {
+			t.Errorf("patchValue(%v, %#v) = %#v, want %#v", tt.field, tt.value, got.Interface(), tt.want)
+		}
+	}
+}
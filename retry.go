@@ -0,0 +1,110 @@
+package sql
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"strings"
+	"time"
+
+	"github.com/ioswarm/golik"
+)
+
+// RetryPolicy defines how often and when failed write transactions are repeated.
+type RetryPolicy struct {
+	// MaxAttempts is the number of executions including the first one, values below 2 disable retries.
+	MaxAttempts    int
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+	Multiplier     float64
+	// Jitter randomizes each backoff by the given fraction, e.g. 0.2 for +/-20%.
+	Jitter float64
+	// RetryOn lists the error kinds to retry, ErrTransient if empty.
+	RetryOn []error
+}
+
+var errorKinds = map[string]error{
+	"notfound":   ErrNotFound,
+	"conflict":   ErrConflict,
+	"constraint": ErrConstraint,
+	"transient":  ErrTransient,
+	"timeout":    ErrTimeout,
+	"connection": ErrConnection,
+}
+
+// ParseErrorKinds maps names like "transient" or "timeout" to the Err* kinds.
+func ParseErrorKinds(names []string) ([]error, error) {
+	result := make([]error, 0, len(names))
+	for _, name := range names {
+		kind, ok := errorKinds[strings.ToLower(strings.TrimSpace(name))]
+		if !ok {
+			return nil, fmt.Errorf("Unknown error kind %v", name)
+		}
+		result = append(result, kind)
+	}
+	return result, nil
+}
+
+func (p *RetryPolicy) retryable(err error) bool {
+	kinds := p.RetryOn
+	if len(kinds) == 0 {
+		kinds = []error{ErrTransient}
+	}
+	for _, kind := range kinds {
+		if errors.Is(err, kind) {
+			return true
+		}
+	}
+	return false
+}
+
+// Backoff returns the delay before the given retry, starting with 1.
+func (p *RetryPolicy) Backoff(retry int) time.Duration {
+	multiplier := p.Multiplier
+	if multiplier < 1 {
+		multiplier = 2
+	}
+	backoff := float64(p.InitialBackoff) * math.Pow(multiplier, float64(retry-1))
+	if p.MaxBackoff > 0 && backoff > float64(p.MaxBackoff) {
+		backoff = float64(p.MaxBackoff)
+	}
+	if p.Jitter > 0 {
+		backoff += backoff * p.Jitter * (2*rand.Float64() - 1)
+	}
+	return time.Duration(backoff)
+}
+
+// retry executes fn until it succeeds, fails with an error not covered by the
+// retry policy or the attempts are exhausted. Returned errors are translated.
+func (h *sqlHandler) retry(ctx golik.CloveContext, op string, fn func() error) error {
+	policy := h.retryPolicy
+	if _, ok := ctx.(*txContext); ok || policy == nil || policy.MaxAttempts < 2 {
+		return h.translate(fn())
+	}
+
+	for attempt := 1; ; attempt++ {
+		err := h.translate(fn())
+		if err == nil {
+			if attempt > 1 {
+				ctx.Info("%v on %v succeeded after %d attempts", op, h.table, attempt)
+			}
+			return nil
+		}
+		if attempt >= policy.MaxAttempts || !policy.retryable(err) {
+			if attempt > 1 {
+				ctx.Warn("%v on %v failed after %d attempts: %v", op, h.table, attempt, err)
+			}
+			return err
+		}
+
+		backoff := policy.Backoff(attempt)
+		ctx.Warn("%v on %v failed in attempt %d of %d, retry in %v: %v", op, h.table, attempt, policy.MaxAttempts, backoff, err)
+
+		select {
+		case <-time.After(backoff):
+		case <-baseContext(ctx).Done():
+			return err
+		}
+	}
+}
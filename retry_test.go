@@ -0,0 +1,108 @@
+package sql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/ioswarm/golik"
+)
+
+// testContext is a clove context that discards its log and is never cancelled.
+type testContext struct {
+	golik.CloveContext
+}
+
+func (c *testContext) Debug(string, ...interface{})      {}
+func (c *testContext) Info(string, ...interface{})       {}
+func (c *testContext) Warn(string, ...interface{})       {}
+func (c *testContext) Error(string, ...interface{})      {}
+func (c *testContext) AddOption(string, interface{})     {}
+func (c *testContext) Deadline() (time.Time, bool)       { return time.Time{}, false }
+func (c *testContext) Done() <-chan struct{}             { return nil }
+func (c *testContext) Err() error                        { return nil }
+func (c *testContext) Value(key interface{}) interface{} { return nil }
+
+func TestBackoff(t *testing.T) {
+	policy := &RetryPolicy{InitialBackoff: 10 * time.Millisecond, MaxBackoff: 30 * time.Millisecond}
+	for retry, want := range []time.Duration{10 * time.Millisecond, 20 * time.Millisecond, 30 * time.Millisecond, 30 * time.Millisecond} {
+		if got := policy.Backoff(retry + 1); got != want {
+			t.Errorf("Backoff(%d) = %v, want %v", retry+1, got, want)
+		}
+	}
+
+	policy = &RetryPolicy{InitialBackoff: 100 * time.Millisecond, Multiplier: 3, Jitter: 0.2}
+	for i := 0; i < 100; i++ {
+		if got := policy.Backoff(2); got < 240*time.Millisecond || got > 360*time.Millisecond {
+			t.Fatalf("Backoff(2) = %v, want 300ms +/-20%%", got)
+		}
+	}
+}
+
+func TestParseErrorKinds(t *testing.T) {
+	kinds, err := ParseErrorKinds([]string{"transient", " Timeout"})
+	if err != nil || !reflect.DeepEqual(kinds, []error{ErrTransient, ErrTimeout}) {
+		t.Errorf("ParseErrorKinds = %v, %v", kinds, err)
+	}
+	if _, err := ParseErrorKinds([]string{"fatal"}); err == nil {
+		t.Error("expected unknown error kind")
+	}
+}
+
+func TestRetry(t *testing.T) {
+	transient := &Error{Kind: ErrTransient}
+	conflict := &Error{Kind: ErrConflict}
+
+	tests := []struct {
+		name     string
+		policy   *RetryPolicy
+		failures []error
+		calls    int
+		err      error
+	}{
+		{"success", &RetryPolicy{MaxAttempts: 3}, nil, 1, nil},
+		{"transient then success", &RetryPolicy{MaxAttempts: 3}, []error{transient, transient}, 3, nil},
+		{"exhausted", &RetryPolicy{MaxAttempts: 2}, []error{transient, transient, transient}, 2, ErrTransient},
+		{"not retryable", &RetryPolicy{MaxAttempts: 3}, []error{conflict}, 1, ErrConflict},
+		{"retry on conflict", &RetryPolicy{MaxAttempts: 3, RetryOn: []error{ErrConflict}}, []error{conflict}, 2, nil},
+		{"disabled", nil, []error{transient}, 1, ErrTransient},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, err := NewHandler(&HandlerSettings{Database: &sql.DB{}, Dialect: Postgres, Type: reflect.TypeOf(testItem{}), Retry: tt.policy})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			calls := 0
+			err = h.(*sqlHandler).retry(&testContext{}, "Test", func() error {
+				calls++
+				if calls <= len(tt.failures) {
+					return tt.failures[calls-1]
+				}
+				return nil
+			})
+			if calls != tt.calls {
+				t.Errorf("calls = %d, want %d", calls, tt.calls)
+			}
+			if (tt.err == nil) != (err == nil) || (tt.err != nil && !errors.Is(err, tt.err)) {
+				t.Errorf("err = %v, want %v", err, tt.err)
+			}
+		})
+	}
+
+	// operations of a unit of work are never retried, the unit of work is repeated as a whole
+	h, _ := NewHandler(&HandlerSettings{Database: &sql.DB{}, Dialect: Postgres, Type: reflect.TypeOf(testItem{}), Retry: &RetryPolicy{MaxAttempts: 3}})
+	calls := 0
+	h.(*sqlHandler).retry(&txContext{CloveContext: &testContext{}, c: context.Background()}, "Test", func() error {
+		calls++
+		return transient
+	})
+	if calls != 1 {
+		t.Errorf("calls in unit of work = %d, want 1", calls)
+	}
+}
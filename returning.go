@@ -0,0 +1,150 @@
+package sql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/ioswarm/golik"
+)
+
+// DeleteReturner is implemented by dialects that return the deleted row of a delete statement.
+type DeleteReturner interface {
+	// DeleteReturning changes the given delete of a single row to return the given columns, if supported.
+	DeleteReturning(delete string, columns []string) string
+}
+
+func (d *db2Dialect) DeleteReturning(delete string, columns []string) string {
+	return fmt.Sprintf("SELECT %v FROM OLD TABLE (%v)", strings.Join(columns, ", "), delete)
+}
+
+func (d *postgresDialect) DeleteReturning(delete string, columns []string) string {
+	return fmt.Sprintf("%v RETURNING %v", delete, strings.Join(columns, ", "))
+}
+
+func (d *sqliteDialect) DeleteReturning(delete string, columns []string) string {
+	return ""
+}
+
+func (d *sqlServerDialect) DeleteReturning(delete string, columns []string) string {
+	output := make([]string, len(columns))
+	for i, c := range columns {
+		output[i] = "DELETED." + c
+	}
+	i := strings.LastIndex(delete, " WHERE ")
+	return fmt.Sprintf("%v OUTPUT %v%v", delete[:i], strings.Join(output, ", "), delete[i:])
+}
+
+// reread reads the row of the given id into the entity within the transaction,
+// soft deleted rows included.
+func (h *sqlHandler) reread(c context.Context, ctx golik.CloveContext, tx *sql.Tx, id interface{}, entity interface{}) (bool, error) {
+	qry := Rebind(h.dialect, fmt.Sprintf("%v WHERE %v = ?", h.buildSelectAll(), h.quote(h.indexCol)))
+	ctx.Debug("Execute query: %v", qry)
+	rows, err := tx.QueryContext(c, qry, id)
+	if err != nil {
+		return false, err
+	}
+	return h.readInto(rows, entity)
+}
+
+// rereadBy reads the row with the given values of the key fields into the entity within
+// the transaction, soft deleted rows included.
+func (h *sqlHandler) rereadBy(c context.Context, ctx golik.CloveContext, tx *sql.Tx, keys []Field, vals []interface{}, entity interface{}) (bool, error) {
+	cond := make([]string, len(keys))
+	for i, key := range keys {
+		cond[i] = h.quote(key.SQLName()) + " = ?"
+	}
+	qry := Rebind(h.dialect, fmt.Sprintf("%v WHERE %v", h.buildSelectAll(), strings.Join(cond, " AND ")))
+	ctx.Debug("Execute query: %v", qry)
+	rows, err := tx.QueryContext(c, qry, vals...)
+	if err != nil {
+		return false, err
+	}
+	return h.readInto(rows, entity)
+}
+
+// exists tells whether an active entity of the given id exists.
+func (h *sqlHandler) exists(c context.Context, ctx golik.CloveContext, tx *sql.Tx, id interface{}) (bool, error) {
+	where := h.excludeDeleted("WHERE "+h.quote(h.indexCol)+" = ?", false)
+	qry := Rebind(h.dialect, fmt.Sprintf("SELECT %v FROM %v %v", h.quote(h.indexCol), h.tablePath(), where))
+	ctx.Debug("Execute query: %v", qry)
+	rows, err := tx.QueryContext(c, qry, id)
+	if err != nil {
+		return false, err
+	}
+	defer rows.Close()
+
+	found := rows.Next()
+	return found, rows.Err()
+}
+
+// unchanged explains an update without affected rows. Besides missing entities and outdated
+// versions, some drivers do not count rows whose values did not change.
+func (h *sqlHandler) unchanged(c context.Context, ctx golik.CloveContext, tx *sql.Tx, id interface{}, version int64, checkVersion bool) error {
+	found, err := h.exists(c, ctx, tx, id)
+	if err != nil {
+		return err
+	}
+	if !found {
+		return notFound(id)
+	}
+	if checkVersion {
+		return staleEntity(id, version)
+	}
+	return nil
+}
+
+// remove deletes the entity of the given id within the transaction and returns it.
+func (h *sqlHandler) remove(c context.Context, ctx golik.CloveContext, tx *sql.Tx, id interface{}) (interface{}, error) {
+	entity := reflect.New(h.itype).Interface()
+
+	returning := ""
+	if returner, ok := h.dialect.(DeleteReturner); ok {
+		returning = returner.DeleteReturning(h.buildDelete(), h.quoteAll(h.builder.SqlNames()))
+	}
+	if returning != "" {
+		stmt, err := h.prepare(c, ctx, tx, returning)
+		if err != nil {
+			return nil, err
+		}
+		defer stmt.Close()
+
+		rows, err := stmt.QueryContext(c, id)
+		if err != nil {
+			return nil, err
+		}
+		found, err := h.readInto(rows, entity)
+		if err != nil {
+			return nil, err
+		}
+		if !found {
+			return nil, notFound(id)
+		}
+		return entity, nil
+	}
+
+	found, err := h.reread(c, ctx, tx, id, entity)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, notFound(id)
+	}
+
+	stmt, err := h.prepare(c, ctx, tx, h.buildDelete())
+	if err != nil {
+		return nil, err
+	}
+	defer stmt.Close()
+
+	res, err := stmt.ExecContext(c, id)
+	if err != nil {
+		return nil, err
+	}
+	if affected, err := res.RowsAffected(); err == nil && affected == 0 {
+		return nil, notFound(id)
+	}
+	return entity, nil
+}
@@ -0,0 +1,36 @@
+package sql
+
+import (
+	"database/sql"
+	"reflect"
+	"testing"
+)
+
+func TestDeleteReturning(t *testing.T) {
+	tests := []struct {
+		dialect Dialect
+		want    string
+	}{
+		{DB2, `SELECT "ID", "NAME" FROM OLD TABLE (DELETE FROM "items" WHERE "ID" = ?)`},
+		{Postgres, `DELETE FROM "items" WHERE "ID" = ? RETURNING "ID", "NAME"`},
+		{SQLite, ""},
+		{SQLServer, `DELETE FROM [items] OUTPUT DELETED.[ID], DELETED.[NAME] WHERE [ID] = ?`},
+	}
+
+	for _, tt := range tests {
+		h, err := NewHandler(&HandlerSettings{Database: &sql.DB{}, Dialect: tt.dialect, Type: reflect.TypeOf(testItem{}), Table: "items", QuoteIdentifiers: true})
+		if err != nil {
+			t.Fatal(err)
+		}
+		sh := h.(*sqlHandler)
+
+		if got := tt.dialect.(DeleteReturner).DeleteReturning(sh.buildDelete(), sh.quoteAll(sh.builder.SqlNames())); got != tt.want {
+			t.Errorf("%v DeleteReturning = %q, want %q", tt.dialect.Name(), got, tt.want)
+		}
+	}
+	for _, d := range []Dialect{MySQL, Oracle} {
+		if _, ok := d.(DeleteReturner); ok {
+			t.Errorf("%v does not return deleted rows", d.Name())
+		}
+	}
+}
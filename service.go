@@ -2,9 +2,7 @@ package sql
 
 import (
 	"database/sql"
-	"fmt"
 	"reflect"
-	"strings"
 	"sync"
 
 	"github.com/ioswarm/golik"
@@ -23,10 +21,34 @@ func NewSql(name string, schema string, driver string, connection string, system
 }
 
 func NewSqlService(name string, settings *Settings, system golik.Golik) (*SqlService, error) {
+	dialect, err := dialectOf(settings)
+	if err != nil {
+		return nil, err
+	}
+
+	naming, err := namingOf(settings)
+	if err != nil {
+		return nil, err
+	}
+
+	retry, err := retryPolicyOf(settings)
+	if err != nil {
+		return nil, err
+	}
+
+	isolation, err := ParseIsolationLevel(settings.Isolation)
+	if err != nil {
+		return nil, err
+	}
+
 	sqls := &SqlService{
-		name:     name,
-		system:   system,
-		settings: settings,
+		name:      name,
+		system:    system,
+		settings:  settings,
+		dialect:   dialect,
+		naming:    naming,
+		retry:     retry,
+		isolation: isolation,
 	}
 
 	hdl, err := system.ExecuteService(sqls)
@@ -41,12 +63,63 @@ func NewSqlService(name string, settings *Settings, system golik.Golik) (*SqlSer
 	return sqls, nil
 }
 
+func dialectOf(settings *Settings) (Dialect, error) {
+	if settings.Dialect != "" {
+		if d, ok := LookupDialect(settings.Dialect); ok {
+			return d, nil
+		}
+		return nil, golik.Errorf("Unknown sql dialect %v", settings.Dialect)
+	}
+	if d, ok := LookupDialect(settings.Driver); ok {
+		return d, nil
+	}
+	return DB2, nil
+}
+
+func namingOf(settings *Settings) (NamingStrategy, error) {
+	if settings.Naming == "" {
+		return UpperCase, nil
+	}
+	if ns, ok := LookupNamingStrategy(settings.Naming); ok {
+		return ns, nil
+	}
+	return nil, golik.Errorf("Unknown naming strategy %v", settings.Naming)
+}
+
+func retryPolicyOf(settings *Settings) (*RetryPolicy, error) {
+	kinds, err := ParseErrorKinds(settings.RetryOn)
+	if err != nil {
+		return nil, err
+	}
+	return &RetryPolicy{
+		MaxAttempts:    settings.RetryAttempts,
+		InitialBackoff: settings.RetryBackoff,
+		MaxBackoff:     settings.RetryMaxBackoff,
+		Jitter:         settings.RetryJitter,
+		RetryOn:        kinds,
+	}, nil
+}
+
+func (sqls *SqlService) affixes(options map[string]interface{}) NamingAffixes {
+	return NamingAffixes{
+		TablePrefix:  optionString(options, "sql.tablePrefix", sqls.settings.TablePrefix),
+		TableSuffix:  optionString(options, "sql.tableSuffix", sqls.settings.TableSuffix),
+		ColumnPrefix: optionString(options, "sql.columnPrefix", sqls.settings.ColumnPrefix),
+		ColumnSuffix: optionString(options, "sql.columnSuffix", sqls.settings.ColumnSuffix),
+	}
+}
+
 type SqlService struct {
-	name     string
-	system   golik.Golik
-	handler  golik.CloveHandler
-	settings *Settings
-	database *sql.DB
+	name      string
+	system    golik.Golik
+	handler   golik.CloveHandler
+	settings  *Settings
+	dialect   Dialect
+	naming    NamingStrategy
+	retry     *RetryPolicy
+	isolation sql.IsolationLevel
+	database  *sql.DB
+	handlers  handlerRegistry
 
 	mutex sync.Mutex
 }
@@ -70,7 +143,11 @@ func (sqls *SqlService) connect(ctx golik.CloveContext) error {
 		return golik.Errorf("Could not create sql-connection via %v to %v: %v", sqls.Driver(), sqls.Connection(), err)
 	}
 
-	if err := con.Ping(); err != nil {
+	c, cancel := withTimeout(ctx, sqls.settings.ConnectTimeout)
+	defer cancel()
+
+	if err := con.PingContext(c); err != nil {
+		con.Close()
 		ctx.Error("Could not connect via %v to %v: %v", sqls.Driver(), sqls.Connection(), err)
 		return golik.Errorf("Could not connect via %v to %v: %v", sqls.Driver(), sqls.Connection(), err)
 	}
@@ -105,6 +182,15 @@ func (sqls *SqlService) Driver() string {
 	return sqls.settings.Driver
 }
 
+func (sqls *SqlService) Dialect() Dialect {
+	return sqls.dialect
+}
+
+// Naming returns the naming strategy of the service without table and column affixes.
+func (sqls *SqlService) Naming() NamingStrategy {
+	return sqls.naming
+}
+
 func (sqls *SqlService) Connection() string {
 	return sqls.settings.Connection
 }
@@ -137,19 +223,76 @@ func (sqls *SqlService) CreateConnectionPool(settings *golik.ConnectionPoolSetti
 	if _, ok := settings.Options["sql.schema"]; !ok {
 		settings.Options["sql.schema"] = sqls.Schema()
 	}
+	if _, ok := settings.Options["sql.dialect"]; !ok {
+		settings.Options["sql.dialect"] = sqls.Dialect()
+	}
+	if _, ok := settings.Options["sql.quoteIdentifiers"]; !ok {
+		settings.Options["sql.quoteIdentifiers"] = sqls.settings.QuoteIdentifiers
+	}
+
+	dialect, err := optionDialect(settings.Options, "sql.dialect", sqls.Dialect())
+	if err != nil {
+		return nil, err
+	}
+
+	naming, err := optionNaming(settings.Options, "sql.naming", sqls.Naming())
+	if err != nil {
+		return nil, err
+	}
+	naming = WithAffixes(naming, sqls.affixes(settings.Options))
+
+	sort, err := optionSort(settings.Options, "sql.sort")
+	if err != nil {
+		return nil, err
+	}
+
+	countMode, err := optionCountMode(settings.Options, "sql.countMode", sqls.settings.CountMode)
+	if err != nil {
+		return nil, err
+	}
 
-	tbl := strings.ToUpper(settings.Type.Name())
-	if v, ok := settings.Options["sql.table"]; ok {
-		tbl = fmt.Sprintln(v)
+	retry := sqls.retry
+	if v, ok := settings.Options["sql.retry"].(*RetryPolicy); ok {
+		retry = v
+	} else if _, ok := settings.Options["sql.retryAttempts"]; ok {
+		policy := *sqls.retry
+		policy.MaxAttempts = optionInt(settings.Options, "sql.retryAttempts", policy.MaxAttempts)
+		retry = &policy
 	}
 
-	sch := ""
-	if v, ok := settings.Options["sql.schema"]; ok {
-		sch = fmt.Sprintln(v)
+	audit := Audit{
+		CreatedAt: optionString(settings.Options, "sql.createdAt", ""),
+		CreatedBy: optionString(settings.Options, "sql.createdBy", ""),
+		UpdatedAt: optionString(settings.Options, "sql.updatedAt", ""),
+		UpdatedBy: optionString(settings.Options, "sql.updatedBy", ""),
 	}
 
 	if settings.CreateHandler == nil {
-		settings.CreateHandler = defaultHandlerCreation(sqls.Database(), settings.Type, settings.IndexField, sch, tbl, settings.Behavior)
+		settings.CreateHandler = defaultHandlerCreation(&HandlerSettings{
+			Database:         sqls.Database(),
+			Dialect:          dialect,
+			Type:             settings.Type,
+			IndexField:       settings.IndexField,
+			Schema:           optionString(settings.Options, "sql.schema", ""),
+			Table:            optionString(settings.Options, "sql.table", naming.TableName(settings.Type.Name())),
+			QuoteIdentifiers: optionBool(settings.Options, "sql.quoteIdentifiers", false),
+			Naming:           naming,
+			Sort:             sort,
+			CountMode:        countMode,
+			CountLimit:       optionInt(settings.Options, "sql.countLimit", sqls.settings.CountLimit),
+			QueryTimeout:     optionDuration(settings.Options, "sql.queryTimeout", sqls.settings.QueryTimeout),
+			WriteTimeout:     optionDuration(settings.Options, "sql.writeTimeout", sqls.settings.WriteTimeout),
+			Retry:            retry,
+			VersionField:     optionString(settings.Options, "sql.version", ""),
+			SoftDeleteField:  optionString(settings.Options, "sql.softDelete", ""),
+			Audit:            audit,
+			UpsertKey:        optionStrings(settings.Options, "sql.upsertKey"),
+			BatchSize:        optionInt(settings.Options, "sql.batchSize", DefaultBatchSize),
+			GeneratedFields:  optionStrings(settings.Options, "sql.generated"),
+			Clock:            optionClock(settings.Options, "sql.clock"),
+			Actor:            optionActor(settings.Options, "sql.actor"),
+			Behavior:         settings.Behavior,
+		}, &sqls.handlers)
 	}
 
 	clove := golik.NewConnectionPool(settings)
@@ -11,7 +11,25 @@ type Settings struct {
 	Poolsize           int
 	Connection         string
 	Driver             string
+	Dialect            string
+	QuoteIdentifiers   bool
 	Schema             string
+	Naming             string
+	TablePrefix        string
+	TableSuffix        string
+	ColumnPrefix       string
+	ColumnSuffix       string
+	CountMode          string
+	CountLimit         int
+	ConnectTimeout     time.Duration
+	QueryTimeout       time.Duration
+	WriteTimeout       time.Duration
+	RetryAttempts      int
+	RetryBackoff       time.Duration
+	RetryMaxBackoff    time.Duration
+	RetryJitter        float64
+	RetryOn            []string
+	Isolation          string
 	ConnectionLifeTime time.Duration
 	MaxOpenConnections int
 	MaxIdleConnections int
@@ -22,7 +40,25 @@ func newBaseSettings() *Settings {
 		Poolsize:           viper.GetInt("sql.poolsize"),
 		Connection:         viper.GetString("sql.connection"),
 		Driver:             viper.GetString("sql.driver"),
+		Dialect:            viper.GetString("sql.dialect"),
+		QuoteIdentifiers:   viper.GetBool("sql.quoteIdentifiers"),
 		Schema:             viper.GetString("sql.schema"),
+		Naming:             viper.GetString("sql.naming"),
+		TablePrefix:        viper.GetString("sql.tablePrefix"),
+		TableSuffix:        viper.GetString("sql.tableSuffix"),
+		ColumnPrefix:       viper.GetString("sql.columnPrefix"),
+		ColumnSuffix:       viper.GetString("sql.columnSuffix"),
+		CountMode:          viper.GetString("sql.countMode"),
+		CountLimit:         viper.GetInt("sql.countLimit"),
+		ConnectTimeout:     viper.GetDuration("sql.connectTimeout") * time.Second,
+		QueryTimeout:       viper.GetDuration("sql.queryTimeout") * time.Second,
+		WriteTimeout:       viper.GetDuration("sql.writeTimeout") * time.Second,
+		RetryAttempts:      viper.GetInt("sql.retryAttempts"),
+		RetryBackoff:       viper.GetDuration("sql.retryBackoff") * time.Millisecond,
+		RetryMaxBackoff:    viper.GetDuration("sql.retryMaxBackoff") * time.Millisecond,
+		RetryJitter:        viper.GetFloat64("sql.retryJitter"),
+		RetryOn:            viper.GetStringSlice("sql.retryOn"),
+		Isolation:          viper.GetString("sql.isolation"),
 		ConnectionLifeTime: viper.GetDuration("sql.connectionLifeTime") * time.Second,
 		MaxOpenConnections: viper.GetInt("sql.maxOpenConnections"),
 		MaxIdleConnections: viper.GetInt("sql.maxIdleConnections"),
@@ -51,11 +87,101 @@ func newSettings(name string) *Settings {
 		bs.Driver = viper.GetString(path)
 	}
 
+	path = getPath("dialect")
+	if viper.IsSet(path) {
+		bs.Dialect = viper.GetString(path)
+	}
+
+	path = getPath("quoteIdentifiers")
+	if viper.IsSet(path) {
+		bs.QuoteIdentifiers = viper.GetBool(path)
+	}
+
 	path = getPath("schema")
 	if viper.IsSet(path) {
 		bs.Schema = viper.GetString(path)
 	}
 
+	path = getPath("naming")
+	if viper.IsSet(path) {
+		bs.Naming = viper.GetString(path)
+	}
+
+	path = getPath("tablePrefix")
+	if viper.IsSet(path) {
+		bs.TablePrefix = viper.GetString(path)
+	}
+
+	path = getPath("tableSuffix")
+	if viper.IsSet(path) {
+		bs.TableSuffix = viper.GetString(path)
+	}
+
+	path = getPath("columnPrefix")
+	if viper.IsSet(path) {
+		bs.ColumnPrefix = viper.GetString(path)
+	}
+
+	path = getPath("columnSuffix")
+	if viper.IsSet(path) {
+		bs.ColumnSuffix = viper.GetString(path)
+	}
+
+	path = getPath("countMode")
+	if viper.IsSet(path) {
+		bs.CountMode = viper.GetString(path)
+	}
+
+	path = getPath("countLimit")
+	if viper.IsSet(path) {
+		bs.CountLimit = viper.GetInt(path)
+	}
+
+	path = getPath("connectTimeout")
+	if viper.IsSet(path) {
+		bs.ConnectTimeout = viper.GetDuration(path) * time.Second
+	}
+
+	path = getPath("queryTimeout")
+	if viper.IsSet(path) {
+		bs.QueryTimeout = viper.GetDuration(path) * time.Second
+	}
+
+	path = getPath("writeTimeout")
+	if viper.IsSet(path) {
+		bs.WriteTimeout = viper.GetDuration(path) * time.Second
+	}
+
+	path = getPath("retryAttempts")
+	if viper.IsSet(path) {
+		bs.RetryAttempts = viper.GetInt(path)
+	}
+
+	path = getPath("retryBackoff")
+	if viper.IsSet(path) {
+		bs.RetryBackoff = viper.GetDuration(path) * time.Millisecond
+	}
+
+	path = getPath("retryMaxBackoff")
+	if viper.IsSet(path) {
+		bs.RetryMaxBackoff = viper.GetDuration(path) * time.Millisecond
+	}
+
+	path = getPath("retryJitter")
+	if viper.IsSet(path) {
+		bs.RetryJitter = viper.GetFloat64(path)
+	}
+
+	path = getPath("retryOn")
+	if viper.IsSet(path) {
+		bs.RetryOn = viper.GetStringSlice(path)
+	}
+
+	path = getPath("isolation")
+	if viper.IsSet(path) {
+		bs.Isolation = viper.GetString(path)
+	}
+
 	return bs
 }
 
@@ -64,4 +190,17 @@ func init() {
 	viper.SetDefault("sql.connectionLifeTime", 0)
 	viper.SetDefault("sql.maxOpenConnections", 0)
 	viper.SetDefault("sql.maxIdleConnections", 0)
+	viper.SetDefault("sql.quoteIdentifiers", false)
+	viper.SetDefault("sql.naming", "upper")
+	viper.SetDefault("sql.countMode", "exact")
+	viper.SetDefault("sql.countLimit", 1000)
+	viper.SetDefault("sql.connectTimeout", 30)
+	viper.SetDefault("sql.queryTimeout", 0)
+	viper.SetDefault("sql.writeTimeout", 0)
+	viper.SetDefault("sql.retryAttempts", 1)
+	viper.SetDefault("sql.retryBackoff", 100)
+	viper.SetDefault("sql.retryMaxBackoff", 5000)
+	viper.SetDefault("sql.retryJitter", 0.2)
+	viper.SetDefault("sql.retryOn", []string{"transient"})
+	viper.SetDefault("sql.isolation", "default")
 }
@@ -0,0 +1,138 @@
+package sql
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/ioswarm/golik"
+)
+
+// softDeleteField returns the field marking soft deleted entities, tagged with `sql:",deleted"`
+// by default. Bool fields are set to true on delete, time fields hold the time of deletion
+// and are NULL for active entities.
+func softDeleteField(builder EntityBuilder, name string) (Field, error) {
+	result, err := lookupField(builder, "soft delete", name, func(tag FieldTag) bool { return tag.Deleted })
+	if err != nil || result == nil {
+		return nil, err
+	}
+
+	ftype := result.Field().Type
+	if ftype.Kind() == reflect.Ptr {
+		ftype = ftype.Elem()
+	}
+	if ftype.Kind() != reflect.Bool && ftype != timetype {
+		return nil, fmt.Errorf("Soft delete field %v must be a bool or time", result.Name())
+	}
+	return result, nil
+}
+
+func (h *sqlHandler) deletedFlag() bool {
+	ftype := h.deleted.Field().Type
+	return ftype.Kind() == reflect.Bool || (ftype.Kind() == reflect.Ptr && ftype.Elem().Kind() == reflect.Bool)
+}
+
+// activeValue is the value of the soft delete column of active entities.
+func (h *sqlHandler) activeValue() interface{} {
+	if h.deletedFlag() {
+		return false
+	}
+	return nil
+}
+
+// deletedValue is the value of the soft delete column of deleted entities.
+func (h *sqlHandler) deletedValue() interface{} {
+	if h.deletedFlag() {
+		return true
+	}
+	return h.now()
+}
+
+func (h *sqlHandler) isActive() string {
+	col := h.quote(h.deleted.SQLName())
+	if h.deletedFlag() {
+		return fmt.Sprintf("%v = %v", col, h.dialect.BoolLiteral(false))
+	}
+	return col + " IS NULL"
+}
+
+func (h *sqlHandler) isDeleted() string {
+	col := h.quote(h.deleted.SQLName())
+	if h.deletedFlag() {
+		return fmt.Sprintf("%v = %v", col, h.dialect.BoolLiteral(true))
+	}
+	return col + " IS NOT NULL"
+}
+
+// excludeDeleted restricts the given where-clause to active entities.
+func (h *sqlHandler) excludeDeleted(where string, includeDeleted bool) string {
+	if h.deleted == nil || includeDeleted {
+		return where
+	}
+	return and(where, h.isActive())
+}
+
+// writeActive makes sure new entities are written as active.
+func (h *sqlHandler) writeActive(fields []Field, vals []interface{}) ([]Field, []interface{}) {
+	if h.deleted == nil {
+		return fields, vals
+	}
+	for i, fld := range fields {
+		if fld == h.deleted {
+			vals[i] = h.activeValue()
+			return fields, vals
+		}
+	}
+	return append(fields, h.deleted), append(vals, h.activeValue())
+}
+
+// markDeleted sets the soft delete column of the entity of the given id matching cond
+// to value and returns the entity.
+func (h *sqlHandler) markDeleted(ctx golik.CloveContext, id interface{}, value interface{}, cond string) (interface{}, error) {
+	c, cancel := withTimeout(ctx, h.writeTimeout)
+	defer cancel()
+
+	tx, err := h.begin(c, ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.rollback()
+
+	qry := fmt.Sprintf("UPDATE %v SET %v = ? WHERE %v = ? AND %v", h.tablePath(), h.quote(h.deleted.SQLName()), h.quote(h.indexCol), cond)
+	stmt, err := h.prepare(c, ctx, tx.Tx, qry)
+	if err != nil {
+		return nil, err
+	}
+	defer stmt.Close()
+
+	res, err := stmt.ExecContext(c, value, id)
+	if err != nil {
+		return nil, err
+	}
+	if affected, err := res.RowsAffected(); err == nil && affected == 0 {
+		return nil, notFound(id)
+	}
+
+	entity := reflect.New(h.itype).Interface()
+	found, err := h.reread(c, ctx, tx.Tx, id, entity)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, notFound(id)
+	}
+
+	return entity, tx.commit()
+}
+
+// softDelete marks the entity of the given id as deleted and returns it.
+func (h *sqlHandler) softDelete(ctx golik.CloveContext, id interface{}) (interface{}, error) {
+	return h.markDeleted(ctx, id, h.deletedValue(), h.isActive())
+}
+
+// restore marks the soft deleted entity of the given id as active and returns it.
+func (h *sqlHandler) restore(ctx golik.CloveContext, id interface{}) (interface{}, error) {
+	if h.deleted == nil {
+		return nil, fmt.Errorf("Soft delete is not enabled for table %v", h.table)
+	}
+	return h.markDeleted(ctx, id, h.activeValue(), h.isDeleted())
+}
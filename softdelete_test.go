@@ -0,0 +1,72 @@
+package sql
+
+import (
+	"database/sql"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type testFlagged struct {
+	ID      int64
+	Deleted bool `sql:",deleted"`
+}
+
+type testStamped struct {
+	ID        int64
+	DeletedAt *time.Time `sql:",deleted"`
+}
+
+type testBadDeleted struct {
+	ID      int64
+	Deleted string `sql:",deleted"`
+}
+
+func TestSoftDeleteField(t *testing.T) {
+	if _, err := NewHandler(&HandlerSettings{Database: &sql.DB{}, Dialect: Postgres, Type: reflect.TypeOf(testBadDeleted{})}); err == nil {
+		t.Error("expected error for string soft delete field")
+	}
+}
+
+func TestSoftDelete(t *testing.T) {
+	now := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	tests := []struct {
+		name      string
+		dialect   Dialect
+		itype     reflect.Type
+		where     string
+		active    interface{}
+		deleted   interface{}
+		isDeleted string
+	}{
+		{"postgres flag", Postgres, reflect.TypeOf(testFlagged{}), "WHERE (ID = ?) AND (DELETED = FALSE)", false, true, "DELETED = TRUE"},
+		{"db2 flag", DB2, reflect.TypeOf(testFlagged{}), "WHERE (ID = ?) AND (DELETED = 0)", false, true, "DELETED = 1"},
+		{"time", Postgres, reflect.TypeOf(testStamped{}), "WHERE (ID = ?) AND (DELETEDAT IS NULL)", nil, now, "DELETEDAT IS NOT NULL"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, err := NewHandler(&HandlerSettings{Database: &sql.DB{}, Dialect: tt.dialect, Type: tt.itype, Clock: func() time.Time { return now }})
+			if err != nil {
+				t.Fatal(err)
+			}
+			sh := h.(*sqlHandler)
+
+			if got := sh.excludeDeleted("WHERE ID = ?", false); got != tt.where {
+				t.Errorf("excludeDeleted = %q, want %q", got, tt.where)
+			}
+			if got := sh.excludeDeleted("WHERE ID = ?", true); got != "WHERE ID = ?" {
+				t.Errorf("excludeDeleted including deleted = %q", got)
+			}
+			if got := sh.activeValue(); got != tt.active {
+				t.Errorf("activeValue = %v, want %v", got, tt.active)
+			}
+			if got := sh.deletedValue(); got != tt.deleted {
+				t.Errorf("deletedValue = %v, want %v", got, tt.deleted)
+			}
+			if got := sh.isDeleted(); got != tt.isDeleted {
+				t.Errorf("isDeleted = %q, want %q", got, tt.isDeleted)
+			}
+		})
+	}
+}
@@ -0,0 +1,97 @@
+package sql
+
+import (
+	"fmt"
+	"strings"
+)
+
+type NullsOrder int
+
+const (
+	NullsDefault NullsOrder = iota
+	NullsFirst
+	NullsLast
+)
+
+// Sort describes the ordering by a single entity field.
+type Sort struct {
+	Field      string
+	Descending bool
+	Nulls      NullsOrder
+}
+
+func Asc(field string) Sort {
+	return Sort{Field: field}
+}
+
+func Desc(field string) Sort {
+	return Sort{Field: field, Descending: true}
+}
+
+// ParseSort parses comma separated sort specifications like "-createdAt nulls last, name".
+// A leading '-' sorts descending, a leading '+' ascending.
+func ParseSort(spec string) ([]Sort, error) {
+	result := make([]Sort, 0)
+	for _, part := range strings.Split(spec, ",") {
+		words := strings.Fields(part)
+		if len(words) == 0 {
+			continue
+		}
+
+		s := Sort{Field: words[0]}
+		switch s.Field[0] {
+		case '-':
+			s.Descending = true
+			s.Field = s.Field[1:]
+		case '+':
+			s.Field = s.Field[1:]
+		}
+		if s.Field == "" {
+			return nil, fmt.Errorf("Missing sort field in %q", part)
+		}
+
+		switch strings.ToLower(strings.Join(words[1:], " ")) {
+		case "":
+		case "nulls first":
+			s.Nulls = NullsFirst
+		case "nulls last":
+			s.Nulls = NullsLast
+		default:
+			return nil, fmt.Errorf("Invalid sort specification %q", part)
+		}
+		result = append(result, s)
+	}
+	return result, nil
+}
+
+func orderTerm(column string, descending bool) string {
+	if descending {
+		return column + " DESC"
+	}
+	return column + " ASC"
+}
+
+// nullsOrderTerm renders the standard NULLS FIRST/LAST clause.
+func nullsOrderTerm(column string, descending bool, nulls NullsOrder) string {
+	switch nulls {
+	case NullsFirst:
+		return orderTerm(column, descending) + " NULLS FIRST"
+	case NullsLast:
+		return orderTerm(column, descending) + " NULLS LAST"
+	default:
+		return orderTerm(column, descending)
+	}
+}
+
+// emulatedNullsOrderTerm sorts nulls via an additional case expression for databases
+// without NULLS FIRST/LAST.
+func emulatedNullsOrderTerm(column string, descending bool, nulls NullsOrder) string {
+	switch nulls {
+	case NullsFirst:
+		return fmt.Sprintf("CASE WHEN %v IS NULL THEN 0 ELSE 1 END, %v", column, orderTerm(column, descending))
+	case NullsLast:
+		return fmt.Sprintf("CASE WHEN %v IS NULL THEN 1 ELSE 0 END, %v", column, orderTerm(column, descending))
+	default:
+		return orderTerm(column, descending)
+	}
+}
@@ -0,0 +1,75 @@
+package sql
+
+import (
+	"database/sql"
+	"reflect"
+	"testing"
+)
+
+func TestParseSort(t *testing.T) {
+	tests := []struct {
+		spec string
+		want []Sort
+		err  bool
+	}{
+		{spec: "", want: []Sort{}},
+		{spec: "name", want: []Sort{Asc("name")}},
+		{spec: "-createdAt nulls last, +name", want: []Sort{{Field: "createdAt", Descending: true, Nulls: NullsLast}, Asc("name")}},
+		{spec: "name NULLS FIRST,,-id", want: []Sort{{Field: "name", Nulls: NullsFirst}, Desc("id")}},
+		{spec: "-", err: true},
+		{spec: "name nulls", err: true},
+		{spec: "name desc", err: true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseSort(tt.spec)
+		if tt.err {
+			if err == nil {
+				t.Errorf("ParseSort(%q) = %v, expected error", tt.spec, got)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("ParseSort(%q) failed: %v", tt.spec, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ParseSort(%q) = %+v, want %+v", tt.spec, got, tt.want)
+		}
+	}
+}
+
+func TestOrderBy(t *testing.T) {
+	sorts := []Sort{{Field: "name", Descending: true, Nulls: NullsLast}}
+	tests := []struct {
+		dialect Dialect
+		want    string
+	}{
+		{Postgres, "NAME DESC NULLS LAST, ID ASC"},
+		{DB2, "NAME DESC NULLS LAST, ID ASC"},
+		{MySQL, "CASE WHEN NAME IS NULL THEN 1 ELSE 0 END, NAME DESC, ID ASC"},
+		{SQLServer, "CASE WHEN NAME IS NULL THEN 1 ELSE 0 END, NAME DESC, ID ASC"},
+	}
+
+	for _, tt := range tests {
+		h, err := NewHandler(&HandlerSettings{Database: &sql.DB{}, Dialect: tt.dialect, Type: reflect.TypeOf(testItem{})})
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := h.(*sqlHandler).orderBy(sorts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != tt.want {
+			t.Errorf("%v orderBy = %q, want %q", tt.dialect.Name(), got, tt.want)
+		}
+	}
+
+	h, _ := NewHandler(&HandlerSettings{Database: &sql.DB{}, Dialect: Postgres, Type: reflect.TypeOf(testItem{})})
+	if got, _ := h.(*sqlHandler).orderBy([]Sort{Desc("id")}); got != "ID DESC" {
+		t.Errorf("orderBy = %q, want the index field only once", got)
+	}
+	if _, err := h.(*sqlHandler).orderBy([]Sort{Asc("unknown")}); err == nil {
+		t.Error("expected unknown sort field")
+	}
+}
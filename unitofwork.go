@@ -0,0 +1,251 @@
+package sql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/ioswarm/golik"
+)
+
+// ErrTxDone is returned by a unit of work which was already committed or rolled back.
+var ErrTxDone = errors.New("Unit of work is already committed or rolled back")
+
+var isolationLevels = map[string]sql.IsolationLevel{
+	"default":          sql.LevelDefault,
+	"read uncommitted": sql.LevelReadUncommitted,
+	"read committed":   sql.LevelReadCommitted,
+	"write committed":  sql.LevelWriteCommitted,
+	"repeatable read":  sql.LevelRepeatableRead,
+	"snapshot":         sql.LevelSnapshot,
+	"serializable":     sql.LevelSerializable,
+	"linearizable":     sql.LevelLinearizable,
+}
+
+// ParseIsolationLevel parses isolation level names like "read committed" or "serializable",
+// underscores and dashes may be used instead of spaces.
+func ParseIsolationLevel(name string) (sql.IsolationLevel, error) {
+	key := strings.ToLower(strings.NewReplacer("_", " ", "-", " ").Replace(strings.TrimSpace(name)))
+	if key == "" {
+		return sql.LevelDefault, nil
+	}
+	if level, ok := isolationLevels[key]; ok {
+		return level, nil
+	}
+	return sql.LevelDefault, fmt.Errorf("Unknown isolation level %v", name)
+}
+
+// txContext is the context of commands enlisted in a unit of work.
+type txContext struct {
+	golik.CloveContext
+	c  context.Context
+	tx *sql.Tx
+}
+
+// handlerTx is the transaction of a single handler operation. Transactions of a unit
+// of work are neither committed nor rolled back by the operation.
+type handlerTx struct {
+	*sql.Tx
+	owned bool
+}
+
+func (tx *handlerTx) commit() error {
+	if !tx.owned {
+		return nil
+	}
+	return tx.Commit()
+}
+
+func (tx *handlerTx) rollback() error {
+	if !tx.owned {
+		return nil
+	}
+	return tx.Rollback()
+}
+
+// begin returns the transaction of the unit of work of the given context or a new transaction.
+func (h *sqlHandler) begin(c context.Context, ctx golik.CloveContext) (*handlerTx, error) {
+	if tc, ok := ctx.(*txContext); ok {
+		return &handlerTx{Tx: tc.tx}, nil
+	}
+	tx, err := h.database.BeginTx(c, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &handlerTx{Tx: tx, owned: true}, nil
+}
+
+// handlerRegistry holds the handlers of the connection pools of a service by entity type
+// and table, a restarted pool replaces the handler of its table.
+type handlerRegistry struct {
+	mutex    sync.RWMutex
+	handlers map[reflect.Type]map[string]*sqlHandler
+}
+
+func (h *sqlHandler) tableName() string {
+	if h.schema == "" {
+		return h.table
+	}
+	return h.schema + "." + h.table
+}
+
+func (r *handlerRegistry) register(h golik.Handler) {
+	sh, ok := h.(*sqlHandler)
+	if !ok {
+		return
+	}
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if r.handlers == nil {
+		r.handlers = make(map[reflect.Type]map[string]*sqlHandler)
+	}
+	if r.handlers[sh.itype] == nil {
+		r.handlers[sh.itype] = make(map[string]*sqlHandler)
+	}
+	r.handlers[sh.itype][sh.tableName()] = sh
+}
+
+// lookup returns the handler of the given type and table, the table may be empty
+// if a single pool handles the type.
+func (r *handlerRegistry) lookup(itype reflect.Type, table string) (*sqlHandler, error) {
+	if itype.Kind() == reflect.Ptr {
+		itype = itype.Elem()
+	}
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	tables := r.handlers[itype]
+	if table != "" {
+		if h, ok := tables[table]; ok {
+			return h, nil
+		}
+		return nil, fmt.Errorf("No connection pool of type %v and table %v", itype, table)
+	}
+	switch len(tables) {
+	case 0:
+		return nil, fmt.Errorf("No connection pool of type %v", itype)
+	case 1:
+		for _, h := range tables {
+			return h, nil
+		}
+	}
+	return nil, fmt.Errorf("Type %v is handled by several connection pools, enlist the command with its table", itype)
+}
+
+// UnitOfWork executes commands of several connection pools of a service in one transaction.
+// All pools must share the database of the service. A unit of work must not be used concurrently.
+type UnitOfWork struct {
+	registry *handlerRegistry
+	database *sql.DB
+	ctx      *txContext
+	done     bool
+}
+
+// Begin starts a unit of work with the given isolation level, the configured isolation
+// level of the service is used if none is given.
+func (sqls *SqlService) Begin(ctx golik.CloveContext, isolation ...sql.IsolationLevel) (*UnitOfWork, error) {
+	level := sqls.isolation
+	if len(isolation) > 0 {
+		level = isolation[0]
+	}
+
+	db := sqls.Database()
+	if db == nil {
+		return nil, golik.Errorln("Database connection is nil")
+	}
+
+	c := baseContext(ctx)
+	tx, err := db.BeginTx(c, &sql.TxOptions{Isolation: level})
+	if err != nil {
+		return nil, TranslateError(sqls.dialect, err)
+	}
+
+	return &UnitOfWork{
+		registry: &sqls.handlers,
+		database: db,
+		ctx: &txContext{
+			CloveContext: ctx,
+			c:            c,
+			tx:           tx,
+		},
+	}, nil
+}
+
+// Tx returns the transaction of the unit of work, e.g. to execute statements of other tables.
+func (u *UnitOfWork) Tx() *sql.Tx {
+	return u.ctx.tx
+}
+
+// Enlist executes the given command with the connection pool of the given entity type in
+// the transaction of the unit of work and returns the result of the command. Supported are
+// the create, get, update and delete commands of golik and the ReadCommand, PatchCommand,
+// UpsertCommand, RestoreCommand and PurgeCommand. Failed commands are not retried.
+func (u *UnitOfWork) Enlist(itype reflect.Type, cmd interface{}) (interface{}, error) {
+	return u.EnlistTable(itype, "", cmd)
+}
+
+// EnlistTable executes the given command like Enlist with the connection pool of the given
+// entity type and table, e.g. "archive" or "schema.archive", if several pools handle the type.
+func (u *UnitOfWork) EnlistTable(itype reflect.Type, table string, cmd interface{}) (interface{}, error) {
+	if u.done {
+		return nil, ErrTxDone
+	}
+
+	h, err := u.registry.lookup(itype, table)
+	if err != nil {
+		return nil, err
+	}
+	if h.database != u.database {
+		return nil, fmt.Errorf("Connection pool of type %v does not share the database of the unit of work", itype)
+	}
+
+	var result interface{}
+	switch c := cmd.(type) {
+	case *golik.CreateCommand:
+		result, err = c.Entity, h.create(u.ctx, c)
+	case *golik.GetCommand:
+		result, err = h.read(u.ctx, c.Id, false)
+	case *ReadCommand:
+		result, err = h.read(u.ctx, c.Id, c.IncludeDeleted)
+	case *golik.UpdateCommand:
+		result, err = c.Entity, h.update(u.ctx, c)
+	case *golik.DeleteCommand:
+		result, err = h.delete(u.ctx, c)
+	case *PatchCommand:
+		result, err = h.patch(u.ctx, c)
+	case *UpsertCommand:
+		result, err = h.upsert(u.ctx, c)
+	case *RestoreCommand:
+		result, err = h.restore(u.ctx, c.Id)
+	case *PurgeCommand:
+		result, err = h.purge(u.ctx, c.Id)
+	default:
+		return nil, fmt.Errorf("Command %T could not be enlisted in a unit of work", cmd)
+	}
+
+	if err != nil {
+		return nil, h.translate(err)
+	}
+	return result, nil
+}
+
+// Commit commits all enlisted commands.
+func (u *UnitOfWork) Commit() error {
+	if u.done {
+		return ErrTxDone
+	}
+	u.done = true
+	return u.ctx.tx.Commit()
+}
+
+// Rollback discards all enlisted commands. It may be deferred, rollback after commit is a no-op.
+func (u *UnitOfWork) Rollback() error {
+	if u.done {
+		return nil
+	}
+	u.done = true
+	return u.ctx.tx.Rollback()
+}
@@ -0,0 +1,48 @@
+package sql
+
+import (
+	"database/sql"
+	"reflect"
+	"testing"
+)
+
+type testItem struct {
+	ID   int64
+	Name string
+}
+
+func testHandler(t *testing.T, itype reflect.Type, table string) *sqlHandler {
+	h, err := NewHandler(&HandlerSettings{Database: &sql.DB{}, Dialect: Postgres, Type: itype, Table: table})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return h.(*sqlHandler)
+}
+
+func TestHandlerRegistry(t *testing.T) {
+	itype := reflect.TypeOf(testItem{})
+	live := testHandler(t, itype, "rows")
+	archive := testHandler(t, itype, "archive")
+	restarted := testHandler(t, itype, "rows")
+
+	registry := &handlerRegistry{}
+	registry.register(live)
+	if h, err := registry.lookup(reflect.PtrTo(itype), ""); err != nil || h != live {
+		t.Errorf("lookup = %v, %v, want the single handler", h, err)
+	}
+
+	registry.register(archive)
+	registry.register(restarted)
+	if _, err := registry.lookup(itype, ""); err == nil {
+		t.Error("expected ambiguous type")
+	}
+	if h, err := registry.lookup(itype, "rows"); err != nil || h != restarted {
+		t.Errorf("lookup rows = %v, %v, want the restarted handler", h, err)
+	}
+	if h, err := registry.lookup(itype, "archive"); err != nil || h != archive {
+		t.Errorf("lookup archive = %v, %v, want the archive handler", h, err)
+	}
+	if _, err := registry.lookup(reflect.TypeOf(testCustomer{}), ""); err == nil {
+		t.Error("expected unknown type")
+	}
+}
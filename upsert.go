@@ -0,0 +1,110 @@
+package sql
+
+import (
+	"fmt"
+
+	"github.com/ioswarm/golik"
+)
+
+// upsertKeyFields returns the fields identifying existing entities on upsert, the index field
+// if no unique key is configured. Generated fields can not identify entities before they are
+// written, so a generated index field leaves the handler without upsert key.
+func upsertKeyFields(builder EntityBuilder, names []string, indexField string, generated []Field) ([]Field, error) {
+	configured := len(names) > 0
+	if !configured {
+		names = []string{indexField}
+	}
+	result := make([]Field, len(names))
+	for i, name := range names {
+		fld, ok := builder.Field(name)
+		if !ok {
+			return nil, fmt.Errorf("Unknown upsert key field %v", name)
+		}
+		if containsField(generated, fld) {
+			if !configured {
+				return nil, nil
+			}
+			return nil, fmt.Errorf("Upsert key field %v must not be generated", name)
+		}
+		result[i] = fld
+	}
+	return result, nil
+}
+
+// buildUpsert returns the upsert statement of the given fields. Matching entities get all fields
+// except the key, created and version fields, the version is incremented instead.
+func (h *sqlHandler) buildUpsert(flds []Field) string {
+	keep := append(append([]Field{}, h.upsertKey...), h.auditing.createdAt, h.auditing.createdBy, h.version)
+	update := make([]Field, 0, len(flds))
+	for _, fld := range flds {
+		if !containsField(keep, fld) {
+			update = append(update, fld)
+		}
+	}
+
+	increment := make([]string, 0, 1)
+	if h.version != nil {
+		increment = append(increment, h.quote(h.version.SQLName()))
+	}
+
+	return h.dialect.Upsert(h.tablePath(), h.sqlNames(flds), h.parameters(flds), h.sqlNames(h.upsertKey), h.sqlNames(update), increment)
+}
+
+// upsert creates the given entity or updates the existing entity with the same key
+// and returns the written entity as read from the database.
+func (h *sqlHandler) upsert(ctx golik.CloveContext, cmd *UpsertCommand) (interface{}, error) {
+	if len(h.upsertKey) == 0 {
+		return nil, fmt.Errorf("Upsert of %v needs a unique key that is not generated, configure sql.upsertKey", h.itype)
+	}
+
+	c, cancel := withTimeout(ctx, h.writeTimeout)
+	defer cancel()
+
+	h.initVersion(cmd.Entity)
+	_, omitted := h.audit(ctx, cmd.Entity, true)
+	fields := h.insertable(cmd.Entity, omitted...)
+	for _, key := range h.upsertKey {
+		if !containsField(fields, key) {
+			fields = append(fields, key)
+		}
+	}
+	vals, err := h.builder.ValuesOf(cmd.Entity, fields)
+	if err != nil {
+		return nil, err
+	}
+	fields, vals = h.writeActive(fields, vals)
+	keys, err := h.builder.ValuesOf(cmd.Entity, h.upsertKey)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := h.begin(c, ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.rollback()
+
+	stmt, err := h.prepare(c, ctx, tx.Tx, h.buildUpsert(fields))
+	if err != nil {
+		return nil, err
+	}
+	defer stmt.Close()
+
+	if _, err := stmt.ExecContext(c, vals...); err != nil {
+		return nil, err
+	}
+
+	found, err := h.rereadBy(c, ctx, tx.Tx, h.upsertKey, keys, cmd.Entity)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, notFound(keys)
+	}
+
+	if err := tx.commit(); err != nil {
+		return nil, err
+	}
+
+	return cmd.Entity, nil
+}
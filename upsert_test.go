@@ -0,0 +1,57 @@
+package sql
+
+import (
+	"database/sql"
+	"reflect"
+	"testing"
+)
+
+type testUpserted struct {
+	ID      int64 `sql:",generated"`
+	Code    string
+	Name    string
+	Version int64 `sql:",version"`
+}
+
+func TestUpsertKeyFields(t *testing.T) {
+	h, err := NewHandler(&HandlerSettings{Database: &sql.DB{}, Dialect: Postgres, Type: reflect.TypeOf(testUpserted{})})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if key := h.(*sqlHandler).upsertKey; len(key) != 0 {
+		t.Errorf("generated index field must not be the upsert key, got %v", key)
+	}
+
+	if _, err := NewHandler(&HandlerSettings{Database: &sql.DB{}, Dialect: Postgres, Type: reflect.TypeOf(testUpserted{}), UpsertKey: []string{"ID"}}); err == nil {
+		t.Error("expected error for generated upsert key")
+	}
+	if _, err := NewHandler(&HandlerSettings{Database: &sql.DB{}, Dialect: Postgres, Type: reflect.TypeOf(testUpserted{}), UpsertKey: []string{"Unknown"}}); err == nil {
+		t.Error("expected error for unknown upsert key")
+	}
+}
+
+func TestBuildUpsert(t *testing.T) {
+	tests := []struct {
+		dialect Dialect
+		want    string
+	}{
+		{DB2, "MERGE INTO items t USING (VALUES (?, ?, ?)) s (CODE, NAME, VERSION) ON (t.CODE = s.CODE) WHEN MATCHED THEN UPDATE SET NAME = s.NAME, VERSION = t.VERSION + 1 WHEN NOT MATCHED THEN INSERT (CODE, NAME, VERSION) VALUES (s.CODE, s.NAME, s.VERSION)"},
+		{Postgres, "INSERT INTO items AS t (CODE, NAME, VERSION) VALUES (?, ?, ?) ON CONFLICT (CODE) DO UPDATE SET NAME = EXCLUDED.NAME, VERSION = t.VERSION + 1"},
+		{SQLite, "INSERT INTO items AS t (CODE, NAME, VERSION) VALUES (?, ?, ?) ON CONFLICT (CODE) DO UPDATE SET NAME = EXCLUDED.NAME, VERSION = t.VERSION + 1"},
+		{MySQL, "INSERT INTO items (CODE, NAME, VERSION) VALUES (?, ?, ?) ON DUPLICATE KEY UPDATE NAME = VALUES(NAME), VERSION = VERSION + 1"},
+		{SQLServer, "MERGE INTO items t USING (VALUES (?, ?, ?)) AS s (CODE, NAME, VERSION) ON (t.CODE = s.CODE) WHEN MATCHED THEN UPDATE SET NAME = s.NAME, VERSION = t.VERSION + 1 WHEN NOT MATCHED THEN INSERT (CODE, NAME, VERSION) VALUES (s.CODE, s.NAME, s.VERSION);"},
+		{Oracle, "MERGE INTO items t USING (SELECT ? CODE, ? NAME, ? VERSION FROM dual) s ON (t.CODE = s.CODE) WHEN MATCHED THEN UPDATE SET NAME = s.NAME, VERSION = t.VERSION + 1 WHEN NOT MATCHED THEN INSERT (CODE, NAME, VERSION) VALUES (s.CODE, s.NAME, s.VERSION)"},
+	}
+
+	for _, tt := range tests {
+		h, err := NewHandler(&HandlerSettings{Database: &sql.DB{}, Dialect: tt.dialect, Type: reflect.TypeOf(testUpserted{}), Table: "items", UpsertKey: []string{"Code"}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		sh := h.(*sqlHandler)
+
+		if got := sh.buildUpsert(sh.insertable(&testUpserted{Code: "a", Name: "x"})); got != tt.want {
+			t.Errorf("%v: buildUpsert = %q, want %q", tt.dialect.Name(), got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,61 @@
+package sql
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// ErrOptimisticLock is returned by updates of versioned entities if the version was
+// changed in the meantime. It is classified as ErrConflict.
+var ErrOptimisticLock = errors.New("Entity was modified concurrently")
+
+func staleEntity(id interface{}, version int64) error {
+	return &Error{
+		Kind: ErrConflict,
+		Err:  fmt.Errorf("%w: version %d of entity with id %v is outdated", ErrOptimisticLock, version, id),
+	}
+}
+
+// versionField returns the field holding the version of an entity, tagged with `sql:",version"`
+// by default.
+func versionField(builder EntityBuilder, name string) (Field, error) {
+	result, err := lookupField(builder, "version", name, func(tag FieldTag) bool { return tag.Version })
+	if err != nil || result == nil {
+		return nil, err
+	}
+
+	switch result.Field().Type.Kind() {
+	case reflect.Int, reflect.Int16, reflect.Int32, reflect.Int64, reflect.Uint, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return result, nil
+	default:
+		return nil, fmt.Errorf("Version field %v must be an integer", result.Name())
+	}
+}
+
+func (h *sqlHandler) versionOf(entity interface{}) int64 {
+	v, _ := fieldByIndex(reflect.ValueOf(entity).Elem(), h.version.Field().Index, false)
+	switch v.Kind() {
+	case reflect.Uint, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return int64(v.Uint())
+	default:
+		return v.Int()
+	}
+}
+
+func (h *sqlHandler) setVersion(entity interface{}, version int64) {
+	v, _ := fieldByIndex(reflect.ValueOf(entity).Elem(), h.version.Field().Index, true)
+	switch v.Kind() {
+	case reflect.Uint, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		v.SetUint(uint64(version))
+	default:
+		v.SetInt(version)
+	}
+}
+
+// initVersion sets the version of new entities to 1.
+func (h *sqlHandler) initVersion(entity interface{}) {
+	if h.version != nil && h.versionOf(entity) == 0 {
+		h.setVersion(entity, 1)
+	}
+}
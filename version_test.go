@@ -0,0 +1,75 @@
+package sql
+
+import (
+	"database/sql"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+type testVersioned struct {
+	ID      int64
+	Name    string
+	Version int32 `sql:",version"`
+}
+
+type testBadVersion struct {
+	ID      int64
+	Version string `sql:",version"`
+}
+
+func TestStaleEntity(t *testing.T) {
+	err := staleEntity(1, 2)
+	if !errors.Is(err, ErrOptimisticLock) || !errors.Is(err, ErrConflict) {
+		t.Errorf("stale entity %v must be an optimistic lock error and a conflict", err)
+	}
+}
+
+func TestVersionField(t *testing.T) {
+	if _, err := NewHandler(&HandlerSettings{Database: &sql.DB{}, Dialect: Postgres, Type: reflect.TypeOf(testBadVersion{})}); err == nil {
+		t.Error("expected error for string version field")
+	}
+
+	h, err := NewHandler(&HandlerSettings{Database: &sql.DB{}, Dialect: Postgres, Type: reflect.TypeOf(testVersioned{})})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sh := h.(*sqlHandler)
+
+	entity := &testVersioned{ID: 1}
+	sh.initVersion(entity)
+	if entity.Version != 1 {
+		t.Errorf("initial version = %d, want 1", entity.Version)
+	}
+	sh.setVersion(entity, 5)
+	if v := sh.versionOf(entity); v != 5 {
+		t.Errorf("version = %d, want 5", v)
+	}
+}
+
+func TestBuildUpdateVersion(t *testing.T) {
+	tests := []struct {
+		dialect Dialect
+		want    string
+	}{
+		{DB2, `UPDATE "items" SET "NAME" = ?, "VERSION" = "VERSION" + 1 WHERE "ID" = ? AND "VERSION" = ?`},
+		{Postgres, `UPDATE "items" SET "NAME" = ?, "VERSION" = "VERSION" + 1 WHERE "ID" = ? AND "VERSION" = ?`},
+		{SQLite, `UPDATE "items" SET "NAME" = ?, "VERSION" = "VERSION" + 1 WHERE "ID" = ? AND "VERSION" = ?`},
+		{MySQL, "UPDATE `items` SET `NAME` = ?, `VERSION` = `VERSION` + 1 WHERE `ID` = ? AND `VERSION` = ?"},
+		{SQLServer, `UPDATE [items] SET [NAME] = ?, [VERSION] = [VERSION] + 1 WHERE [ID] = ? AND [VERSION] = ?`},
+		{Oracle, `UPDATE "items" SET "NAME" = ?, "VERSION" = "VERSION" + 1 WHERE "ID" = ? AND "VERSION" = ?`},
+	}
+
+	for _, tt := range tests {
+		h, err := NewHandler(&HandlerSettings{Database: &sql.DB{}, Dialect: tt.dialect, Type: reflect.TypeOf(testVersioned{}), Table: "items", QuoteIdentifiers: true})
+		if err != nil {
+			t.Fatal(err)
+		}
+		sh := h.(*sqlHandler)
+
+		fields := sh.builder.Writable(&testVersioned{Name: "x"}, sh.writeExpects()...)
+		if got := sh.buildUpdate(fields, true); got != tt.want {
+			t.Errorf("%T: buildUpdate = %q, want %q", tt.dialect, got, tt.want)
+		}
+	}
+}